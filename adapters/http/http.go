@@ -2,6 +2,7 @@ package httpadapter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -9,8 +10,9 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"time"
 
-	"github.com/kpango/glg"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 )
 
 // HTTPClient implementation
@@ -21,8 +23,15 @@ type HTTPClient interface {
 type httpAdapter struct {
 	client  HTTPClient
 	baseUrl string
+	retry   RetryPolicy
+	limiter *RateLimiter
+	log     logger.Logger
+	agent   string
 }
 
+// Option customizes the httpAdapter
+type Option func(*httpAdapter)
+
 var (
 	// ErrorNilData no data
 	ErrorNilData = errors.New("no data to parse ")
@@ -30,29 +39,115 @@ var (
 	ErrorNilAuth = errors.New("no auth to parse ")
 )
 
-// New returns an httpAdapter
-func New(client HTTPClient, baseUrl string) *httpAdapter {
-	return &httpAdapter{client, baseUrl}
+// New returns an httpAdapter, by default idempotent requests
+// are retried with DefaultRetryPolicy and nothing is logged
+func New(client HTTPClient, baseUrl string, opts ...Option) *httpAdapter {
+	h := &httpAdapter{client: client, baseUrl: baseUrl, retry: DefaultRetryPolicy(), log: logger.Nop()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// WithRetryPolicy replaces the default retry policy
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(h *httpAdapter) {
+		h.retry = policy
+	}
+}
+
+// WithUserAgent sets the User-Agent header of requests that do not set one
+func WithUserAgent(userAgent string) Option {
+	return func(h *httpAdapter) {
+		h.agent = userAgent
+	}
+}
+
+// WithLogger sets the adapter logger, tokens and customer
+// PII are redacted before reaching l
+func WithLogger(l logger.Logger) Option {
+	return func(h *httpAdapter) {
+		if l != nil {
+			h.log = logger.Redact(l)
+		}
+	}
+}
+
+// Logger returns the adapter logger, services built on
+// top of the adapter log through it
+func (h *httpAdapter) Logger() logger.Logger {
+	return h.log
 }
 
 // DoRequest is the httpAdapter requester
 func (h *httpAdapter) DoRequest(method, path string, reader io.Reader, headers map[string]string) (response []byte, status int, err error) {
-	glg.Debugf("[DoRequest]: method:%v, url:%v\n", method, h.baseUrl+path)
-	request, err := http.NewRequest(method, h.baseUrl+path, reader)
+	return h.DoRequestCtx(context.Background(), method, path, reader, headers)
+}
+
+// DoRequestCtx is the httpAdapter requester bound to ctx,
+// the request is aborted as soon as ctx is done
+//
+// The reader is buffered so the body can be replayed when
+// the retry policy allows another attempt
+func (h *httpAdapter) DoRequestCtx(ctx context.Context, method, path string, reader io.Reader, headers map[string]string) (response []byte, status int, err error) {
+	var body []byte
+	if reader != nil {
+		if body, err = ioutil.ReadAll(reader); err != nil {
+			return nil, 0, err
+		}
+	}
+	retryable := h.retry.allows(ctx, method)
+	for attempt := 1; ; attempt++ {
+		if h.limiter != nil {
+			if err = h.limiter.Wait(ctx, FamilyOf(path)); err != nil {
+				return nil, 0, err
+			}
+		}
+		var retryAfter time.Duration
+		response, status, retryAfter, err = h.do(ctx, method, path, body, reader != nil, headers)
+		if !retryable || attempt >= h.retry.MaxAttempts || !h.retry.shouldRetry(ctx, status, err) {
+			return
+		}
+		wait := h.retry.backoff(attempt)
+		if retryAfter > wait {
+			if h.retry.MaxDelay > 0 && retryAfter > h.retry.MaxDelay {
+				return
+			}
+			wait = retryAfter
+		}
+		h.log.Debug("[DoRequest]: retrying", logger.F("method", method), logger.F("url", h.baseUrl+path),
+			logger.F("wait", wait), logger.F("attempt", attempt), logger.F("status", status), logger.Err(err))
+		if err = sleep(ctx, wait); err != nil {
+			return nil, 0, err
+		}
+	}
+}
+
+func (h *httpAdapter) do(ctx context.Context, method, path string, body []byte, hasBody bool, headers map[string]string) (response []byte, status int, retryAfter time.Duration, err error) {
+	h.log.Debug("[DoRequest]: request", logger.F("method", method), logger.F("url", h.baseUrl+path))
+	var reader io.Reader
+	if hasBody {
+		reader = bytes.NewReader(body)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, h.baseUrl+path, reader)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
 	for k, v := range headers {
 		request.Header.Add(k, v)
 	}
+	if h.agent != "" && request.Header.Get("User-Agent") == "" {
+		request.Header.Set("User-Agent", h.agent)
+	}
 	resp, err := h.client.Do(request)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, 0, err
 	}
-	defer closeBodyReader(resp.Body)
+	defer h.closeBodyReader(resp.Body)
 	result, err := ioutil.ReadAll(resp.Body)
-	glg.Debug("[DoRequest]: resp ", string(result))
-	return result, resp.StatusCode, err
+	h.log.Debug("[DoRequest]: response", logger.F("status", resp.StatusCode), logger.F("body", result))
+	retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return result, resp.StatusCode, retryAfter, err
 }
 
 // NewJsonReader returns a reader from a given data
@@ -62,7 +157,6 @@ func NewJsonReader(data interface{}) (io.Reader, error) {
 	}
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		glg.Warnf("Error on makeReader marshaling json data: %e", err)
 		return nil, errors.New("error on marshal data: " + err.Error())
 	}
 	return bytes.NewReader(jsonData), nil
@@ -77,14 +171,12 @@ func NewMultipartReader(data interface{}) (reader io.Reader, boundary string, er
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		err = errors.New("error on marshal data: " + err.Error())
-		glg.Warnf("Error on makeReader marshaling json data: %e", err)
 		return
 	}
 	body := &bytes.Buffer{}
 	writer, err := getWriter(body, jsonData)
 	if err != nil {
 		err = errors.New("error on create part data: " + err.Error())
-		glg.Warnf("Error on writing metadata headers: %v", err)
 		return
 	}
 	return bytes.NewReader(body.Bytes()), writer.Boundary(), nil
@@ -98,25 +190,22 @@ func getWriter(body *bytes.Buffer, data []byte) (*multipart.Writer, error) {
 	part, err := writer.CreatePart(metadataHeader)
 	if err != nil {
 		err = errors.New("error on create part data: " + err.Error())
-		glg.Warnf("Error on writing metadata headers: %v", err)
 		return nil, err
 	}
 	_, err = part.Write(data)
 	if err != nil {
 		err = errors.New("error on create part data: " + err.Error())
-		glg.Warnf("Error on writing data: %v", err)
 		return nil, err
 	}
 	if err := writer.Close(); err != nil {
 		err = errors.New("error on create part data: " + err.Error())
-		glg.Fatalf("Error closing multipart writer: %v", err)
 		return nil, err
 	}
 	return writer, nil
 }
 
-func closeBodyReader(reader io.ReadCloser) {
+func (h *httpAdapter) closeBodyReader(reader io.ReadCloser) {
 	if err := reader.Close(); err != nil {
-		glg.Warnf("Error on closeBodyReader %e", err)
+		h.log.Warn("[DoRequest]: closing response body", logger.Err(err))
 	}
 }
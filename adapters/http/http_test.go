@@ -2,11 +2,16 @@ package httpadapter
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 	"github.com/arxdsilva/golang-ifood-sdk/mocks"
 	"github.com/stretchr/testify/assert"
 )
@@ -67,3 +72,58 @@ func TestNewMultipartReader_OK(t *testing.T) {
 	assert.NotNil(t, reader)
 	assert.NotEmpty(t, boudary)
 }
+
+func TestHttpAdapter_DoRequestCtx_Canceled(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}),
+	)
+	defer ts.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	adapter := New(http.DefaultClient, ts.URL)
+	resp, status, err := adapter.DoRequestCtx(ctx, http.MethodGet, "/", nil, nil)
+	assert.Nil(t, resp)
+	assert.Equal(t, 0, status)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestHttpAdapter_WithLogger_Redacts(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"accessToken":"secret-token","customer":{"phone":{"number":"0800 123"}}}`))
+		}),
+	)
+	defer ts.Close()
+	out := &bytes.Buffer{}
+	adapter := New(http.DefaultClient, ts.URL, WithLogger(logger.NewText(out, logger.LevelDebug)))
+	_, status, err := adapter.DoRequest(http.MethodGet, "/", nil, map[string]string{"Authorization": "Bearer secret-token"})
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.Contains(out.String(), "[DoRequest]: response"))
+	assert.False(t, strings.Contains(out.String(), "secret-token"))
+	assert.False(t, strings.Contains(out.String(), "0800 123"))
+}
+
+func TestHttpAdapter_Logger_DefaultNop(t *testing.T) {
+	adapter := New(http.DefaultClient, "test")
+	assert.Equal(t, logger.Nop(), adapter.Logger())
+	assert.Equal(t, logger.Nop(), logger.Of(adapter))
+}
+
+func TestHttpAdapter_WithUserAgent(t *testing.T) {
+	var agents []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			agents = append(agents, r.UserAgent())
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithUserAgent("my-pos/1.0"))
+	_, _, err := adapter.DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Nil(t, err)
+	_, _, err = adapter.DoRequest(http.MethodGet, "/", nil, map[string]string{"User-Agent": "custom"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"my-pos/1.0", "custom"}, agents)
+}
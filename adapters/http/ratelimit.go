@@ -0,0 +1,185 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Family groups the iFood endpoints that share a quota
+type Family string
+
+const (
+	// FamilyAuth are the oauth endpoints
+	FamilyAuth Family = "auth"
+	// FamilyEvents are the polling and acknowledgment endpoints
+	FamilyEvents Family = "events"
+	// FamilyOrders are the order details and status endpoints
+	FamilyOrders Family = "orders"
+	// FamilyCatalog are the catalog, product and pizza endpoints
+	FamilyCatalog Family = "catalog"
+	// FamilyMerchant are the merchant, availability and interruption endpoints
+	FamilyMerchant Family = "merchant"
+	// FamilyOther is any endpoint not listed above
+	FamilyOther Family = "other"
+)
+
+// ErrRateLimited is returned by a fail fast limiter with an empty bucket
+var ErrRateLimited = errors.New("client side rate limit reached")
+
+type (
+	// Limit configures a token bucket: Rate requests every Per,
+	// accumulating up to Burst tokens (defaults to Rate)
+	Limit struct {
+		Rate  int
+		Per   time.Duration
+		Burst int
+	}
+
+	// RateLimiter is a token bucket limiter per endpoint family,
+	// it is safe for concurrent use and meant to be shared by
+	// every adapter talking to the same iFood account
+	RateLimiter struct {
+		mu       sync.Mutex
+		buckets  map[Family]*bucket
+		failFast bool
+	}
+
+	bucket struct {
+		limit  Limit
+		tokens float64
+		last   time.Time
+	}
+)
+
+// DefaultLimits are conservative limits below the iFood quotas,
+// polling is recommended every 30s so events get a small bucket
+func DefaultLimits() map[Family]Limit {
+	return map[Family]Limit{
+		FamilyAuth:     {Rate: 5, Per: time.Second, Burst: 5},
+		FamilyEvents:   {Rate: 5, Per: time.Second, Burst: 10},
+		FamilyOrders:   {Rate: 20, Per: time.Second, Burst: 40},
+		FamilyCatalog:  {Rate: 10, Per: time.Second, Burst: 20},
+		FamilyMerchant: {Rate: 10, Per: time.Second, Burst: 20},
+	}
+}
+
+// NewRateLimiter returns a limiter with the given limits, families
+// without a limit are not throttled. When failFast is set, calls
+// return ErrRateLimited instead of queueing for a token
+func NewRateLimiter(limits map[Family]Limit, failFast bool) *RateLimiter {
+	r := &RateLimiter{buckets: make(map[Family]*bucket), failFast: failFast}
+	for family, limit := range limits {
+		r.SetLimit(family, limit)
+	}
+	return r
+}
+
+// WithRateLimiter makes the adapter wait on limiter before every attempt
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(h *httpAdapter) {
+		h.limiter = limiter
+	}
+}
+
+// SetLimit replaces the limit of a family, a zero Rate removes it
+func (r *RateLimiter) SetLimit(family Family, limit Limit) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if limit.Rate <= 0 {
+		delete(r.buckets, family)
+		return
+	}
+	if limit.Per <= 0 {
+		limit.Per = time.Second
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = limit.Rate
+	}
+	r.buckets[family] = &bucket{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+}
+
+// Allow takes a token from the family bucket without waiting
+func (r *RateLimiter) Allow(family Family) bool {
+	_, ok := r.take(family)
+	return ok
+}
+
+// Wait takes a token from the family bucket, queueing until one is
+// available or ctx is done. A fail fast limiter never waits
+func (r *RateLimiter) Wait(ctx context.Context, family Family) error {
+	for {
+		wait, ok := r.take(family)
+		if ok {
+			return nil
+		}
+		if r.failFast {
+			return ErrRateLimited
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// Utilization returns how much of each family bucket is in use,
+// 0 is an idle family and 1 means callers are queueing
+func (r *RateLimiter) Utilization() map[Family]float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	usage := make(map[Family]float64, len(r.buckets))
+	for family, b := range r.buckets {
+		b.refill(now)
+		usage[family] = 1 - b.tokens/float64(b.limit.Burst)
+	}
+	return usage
+}
+
+// take returns whether a token was taken, or how long until the next one
+func (r *RateLimiter) take(family Family) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.buckets[family]
+	if !ok {
+		return 0, true
+	}
+	b.refill(time.Now())
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	perToken := float64(b.limit.Per) / float64(b.limit.Rate)
+	return time.Duration((1 - b.tokens) * perToken), false
+}
+
+func (b *bucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last)
+	if elapsed <= 0 {
+		return
+	}
+	b.last = now
+	b.tokens += float64(elapsed) / float64(b.limit.Per) * float64(b.limit.Rate)
+	if burst := float64(b.limit.Burst); b.tokens > burst {
+		b.tokens = burst
+	}
+}
+
+// FamilyOf classifies an API path into its endpoint family
+func FamilyOf(path string) Family {
+	switch {
+	case strings.HasPrefix(path, "/authentication/"), strings.HasPrefix(path, "/oauth/"):
+		return FamilyAuth
+	case strings.Contains(path, "/events"), strings.HasSuffix(path, "/acknowledgment"):
+		return FamilyEvents
+	case strings.HasPrefix(path, "/order/"), strings.Contains(path, "/orders"):
+		return FamilyOrders
+	case strings.HasPrefix(path, "/catalog/"):
+		return FamilyCatalog
+	case strings.HasPrefix(path, "/merchant/"), strings.Contains(path, "/merchants"):
+		return FamilyMerchant
+	}
+	return FamilyOther
+}
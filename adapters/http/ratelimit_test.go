@@ -0,0 +1,105 @@
+package httpadapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_AllowBurst(t *testing.T) {
+	limiter := NewRateLimiter(map[Family]Limit{
+		FamilyEvents: {Rate: 1, Per: time.Hour, Burst: 2},
+	}, true)
+	assert.True(t, limiter.Allow(FamilyEvents))
+	assert.True(t, limiter.Allow(FamilyEvents))
+	assert.False(t, limiter.Allow(FamilyEvents))
+	assert.True(t, limiter.Allow(FamilyOrders))
+}
+
+func TestRateLimiter_WaitQueues(t *testing.T) {
+	limiter := NewRateLimiter(map[Family]Limit{
+		FamilyOrders: {Rate: 1, Per: 30 * time.Millisecond},
+	}, false)
+	start := time.Now()
+	assert.Nil(t, limiter.Wait(context.Background(), FamilyOrders))
+	assert.Nil(t, limiter.Wait(context.Background(), FamilyOrders))
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+}
+
+func TestRateLimiter_WaitFailFast(t *testing.T) {
+	limiter := NewRateLimiter(map[Family]Limit{
+		FamilyCatalog: {Rate: 1, Per: time.Hour},
+	}, true)
+	assert.Nil(t, limiter.Wait(context.Background(), FamilyCatalog))
+	assert.Equal(t, ErrRateLimited, limiter.Wait(context.Background(), FamilyCatalog))
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	limiter := NewRateLimiter(map[Family]Limit{
+		FamilyMerchant: {Rate: 1, Per: time.Hour},
+	}, false)
+	assert.Nil(t, limiter.Wait(context.Background(), FamilyMerchant))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, limiter.Wait(ctx, FamilyMerchant))
+}
+
+func TestRateLimiter_Utilization(t *testing.T) {
+	limiter := NewRateLimiter(map[Family]Limit{
+		FamilyEvents: {Rate: 1, Per: time.Hour, Burst: 4},
+	}, true)
+	assert.InDelta(t, 0, limiter.Utilization()[FamilyEvents], 0.01)
+	limiter.Allow(FamilyEvents)
+	limiter.Allow(FamilyEvents)
+	assert.InDelta(t, 0.5, limiter.Utilization()[FamilyEvents], 0.01)
+	limiter.SetLimit(FamilyEvents, Limit{})
+	_, ok := limiter.Utilization()[FamilyEvents]
+	assert.False(t, ok)
+}
+
+func TestFamilyOf(t *testing.T) {
+	cases := map[string]Family{
+		"/authentication/v1.0/oauth/token":            FamilyAuth,
+		"/oauth/token":                                FamilyAuth,
+		"/order/v1.0/events:polling":                  FamilyEvents,
+		"/order/v1.0/acknowledgment":                  FamilyEvents,
+		"/v3.0/events:polling":                        FamilyEvents,
+		"/v1.0/events/acknowledgment":                 FamilyEvents,
+		"/order/v1.0/orders/123/confirm":              FamilyOrders,
+		"/v3.0/orders/123":                            FamilyOrders,
+		"/catalog/v2.0/merchants/123/catalogs":        FamilyCatalog,
+		"/merchant/v2.0/merchants/123/availabilities": FamilyMerchant,
+		"/v1.0/merchants/123/unavailabilities":        FamilyMerchant,
+		"/logistics/v1.0/something":                   FamilyOther,
+	}
+	for path, family := range cases {
+		assert.Equal(t, family, FamilyOf(path), path)
+	}
+}
+
+func TestDoRequest_RateLimited(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer ts.Close()
+	limiter := NewRateLimiter(map[Family]Limit{
+		FamilyEvents: {Rate: 1, Per: time.Hour},
+	}, true)
+	adapter := New(http.DefaultClient, ts.URL, WithRateLimiter(limiter))
+	_, status, err := adapter.DoRequest(http.MethodGet, "/order/v1.0/events:polling", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	_, status, err = adapter.DoRequest(http.MethodGet, "/order/v1.0/events:polling", nil, nil)
+	assert.Equal(t, ErrRateLimited, err)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,148 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy describes how the adapter retries a failed request
+type RetryPolicy struct {
+	// MaxAttempts is the total of tries per request, 1 or less disables retries
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff, a longer Retry-After stops the retries
+	MaxDelay time.Duration
+	// Multiplier grows the delay after every attempt
+	Multiplier float64
+	// Jitter is the randomized fraction of each delay, from 0 to 1
+	Jitter float64
+	// RetryableStatus are the response codes worth another attempt
+	RetryableStatus map[int]bool
+	// RetryNonIdempotent also retries POST and PATCH requests
+	RetryNonIdempotent bool
+}
+
+type idempotentKey struct{}
+
+var idempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodTrace:   true,
+}
+
+// DefaultRetryPolicy retries idempotent requests up to 3 times
+// on 429 and 5xx gateway errors, starting at 200ms
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+		Multiplier:  2,
+		Jitter:      0.5,
+		RetryableStatus: map[int]bool{
+			http.StatusTooManyRequests:     true,
+			http.StatusInternalServerError: true,
+			http.StatusBadGateway:          true,
+			http.StatusServiceUnavailable:  true,
+			http.StatusGatewayTimeout:      true,
+		},
+	}
+}
+
+// NoRetry makes a single attempt per request
+func NoRetry() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// MarkIdempotent flags the request made with ctx as safe to retry,
+// whatever its http method is
+func MarkIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentKey{}, true)
+}
+
+func (p RetryPolicy) allows(ctx context.Context, method string) bool {
+	if p.MaxAttempts <= 1 {
+		return false
+	}
+	if p.RetryNonIdempotent || idempotentMethods[method] {
+		return true
+	}
+	marked, _ := ctx.Value(idempotentKey{}).(bool)
+	return marked
+}
+
+func (p RetryPolicy) shouldRetry(ctx context.Context, status int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return isTransient(err)
+	}
+	return p.RetryableStatus[status]
+}
+
+// backoff returns the wait after the given failed attempt, starting at 1
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(p.BaseDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+	jitter := math.Max(0, math.Min(p.Jitter, 1))
+	delay = delay*(1-jitter) + rand.Float64()*delay*jitter
+	return time.Duration(delay)
+}
+
+func isTransient(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or http-date format
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package httpadapter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func fastRetry() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseDelay = time.Millisecond
+	p.MaxDelay = 10 * time.Millisecond
+	return p
+}
+
+func TestDoRequest_RetriesIdempotent(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithRetryPolicy(fastRetry()))
+	_, status, err := adapter.DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequest_StopsAtMaxAttempts(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithRetryPolicy(fastRetry()))
+	_, status, err := adapter.DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadGateway, status)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequest_NoRetryOnPost(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithRetryPolicy(fastRetry()))
+	_, status, err := adapter.DoRequest(http.MethodPost, "/", strings.NewReader("{}"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequest_RetriesMarkedPostReplayingBody(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, `{"id":"1"}`, string(body))
+			if atomic.AddInt32(&calls, 1) == 1 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithRetryPolicy(fastRetry()))
+	ctx := MarkIdempotent(context.Background())
+	_, status, err := adapter.DoRequestCtx(ctx, http.MethodPost, "/", strings.NewReader(`{"id":"1"}`), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequest_RetryAfterAboveMaxDelay(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithRetryPolicy(fastRetry()))
+	_, status, err := adapter.DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestDoRequest_NoRetryPolicy(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer ts.Close()
+	adapter := New(http.DefaultClient, ts.URL, WithRetryPolicy(NoRetry()))
+	_, status, err := adapter.DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1", now))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon", now))
+	date := now.Add(10 * time.Second).Format(http.TimeFormat)
+	assert.Equal(t, 10*time.Second, parseRetryAfter(date, now))
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second, Multiplier: 2}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10))
+	p.Jitter = 1
+	for i := 0; i < 20; i++ {
+		d := p.backoff(2)
+		assert.True(t, d >= 0 && d <= 200*time.Millisecond)
+	}
+}
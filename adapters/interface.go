@@ -1,8 +1,15 @@
 package adapters
 
-import "io"
+import (
+	"context"
+	"io"
+)
 
 // Http represents the API querier abstraction
+//
+// DoRequestCtx is the context-first variant, cancelling ctx aborts
+// the underlying http request; DoRequest uses context.Background()
 type Http interface {
 	DoRequest(method, path string, reader io.Reader, headers map[string]string) ([]byte, int, error)
+	DoRequestCtx(ctx context.Context, method, path string, reader io.Reader, headers map[string]string) ([]byte, int, error)
 }
@@ -0,0 +1,156 @@
+// Package apierror holds the error returned by every SDK service
+// when the iFood API answers with an unexpected status
+package apierror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotFound the resource does not exist, status 404
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized the credentials were refused, status 401 or 403
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrConflict the resource state does not allow the request, status 409
+	ErrConflict = errors.New("conflict")
+	// ErrRateLimited the request quota is exhausted, status 429
+	ErrRateLimited = errors.New("rate limited")
+	// ErrValidation the request was refused as invalid, status 400 or 422
+	ErrValidation = errors.New("validation failed")
+)
+
+type (
+	// APIError is a non successful iFood API response, match its
+	// category with errors.Is(err, apierror.ErrNotFound) and read
+	// the fields with errors.As(err, &apiErr)
+	APIError struct {
+		// Reason describes the SDK operation that failed
+		Reason  string
+		Status  int
+		Code    string
+		Message string
+		Field   string
+		Details []interface{}
+		// Path is the requested API endpoint
+		Path string
+		// Body is the raw response body
+		Body []byte
+		kind error
+	}
+
+	errorBody struct {
+		Code    string          `json:"code"`
+		Field   string          `json:"field"`
+		Message string          `json:"message"`
+		Details json.RawMessage `json:"details"`
+	}
+
+	// envelope is either {"error": {...}}, a top level errorBody or
+	// an OAuth error {"error": "invalid_grant", "error_description": "..."}
+	envelope struct {
+		Error            json.RawMessage `json:"error"`
+		ErrorDescription string          `json:"error_description"`
+		errorBody
+	}
+)
+
+// New builds an APIError from an API response, decoding the iFood
+// error body when there is one
+func New(reason string, status int, path string, body []byte) *APIError {
+	e := &APIError{Reason: reason, Status: status, Path: path, Body: body}
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return e
+	}
+	eb := env.errorBody
+	var nested errorBody
+	var oauthCode string
+	switch {
+	case len(env.Error) == 0 || string(env.Error) == "null":
+	case json.Unmarshal(env.Error, &nested) == nil:
+		eb = nested
+	case json.Unmarshal(env.Error, &oauthCode) == nil:
+		eb.Code, eb.Message = oauthCode, env.ErrorDescription
+	}
+	e.Code, e.Field, e.Message = eb.Code, eb.Field, eb.Message
+	e.Details = decodeDetails(eb.Details)
+	if e.Code == "" && len(e.Details) == 1 {
+		if detail, ok := e.Details[0].(map[string]interface{}); ok {
+			e.Code, _ = detail["code"].(string)
+		}
+	}
+	return e
+}
+
+// WithKind adds a category matched by errors.Is on top of
+// the one derived from the status code
+func (e *APIError) WithKind(kind error) *APIError {
+	e.kind = kind
+	return e
+}
+
+// Kind returns the category derived from the status code, nil when
+// the status has no category
+func (e *APIError) Kind() error {
+	switch e.Status {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return ErrValidation
+	}
+	return nil
+}
+
+// Is matches the error against its categories
+func (e *APIError) Is(target error) bool {
+	if target == nil {
+		return false
+	}
+	return target == e.Kind() || target == e.kind
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	if e.Reason != "" {
+		b.WriteString(e.Reason)
+	} else {
+		b.WriteString("iFood API request failed")
+	}
+	fmt.Fprintf(&b, " (status %d", e.Status)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", code '%s'", e.Code)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, ", field '%s'", e.Field)
+	}
+	b.WriteString(")")
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+	return b.String()
+}
+
+func decodeDetails(raw json.RawMessage) []interface{} {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	var details []interface{}
+	if err := json.Unmarshal(raw, &details); err == nil {
+		return details
+	}
+	var detail interface{}
+	if err := json.Unmarshal(raw, &detail); err == nil {
+		return []interface{}{detail}
+	}
+	return nil
+}
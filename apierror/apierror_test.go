@@ -0,0 +1,80 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew_EnvelopeBody(t *testing.T) {
+	body := []byte(`{"error":{"code":"BadRequest","field":"orderId","message":"invalid order","details":["a","b"]}}`)
+	err := New("Order could not be confirmed", http.StatusBadRequest, "/order/v1.0/orders/1/confirm", body)
+	assert.Equal(t, "BadRequest", err.Code)
+	assert.Equal(t, "orderId", err.Field)
+	assert.Equal(t, "invalid order", err.Message)
+	assert.Equal(t, []interface{}{"a", "b"}, err.Details)
+	assert.Equal(t, "/order/v1.0/orders/1/confirm", err.Path)
+	assert.Equal(t, body, err.Body)
+	assert.Equal(t, "Order could not be confirmed (status 400, code 'BadRequest', field 'orderId'): invalid order", err.Error())
+}
+
+func TestNew_TopLevelBody(t *testing.T) {
+	body := []byte(`{"message":"item not found","details":{"code":"ITEM_NOT_FOUND"}}`)
+	err := New("", http.StatusNotFound, "/v1.0/items", body)
+	assert.Equal(t, "ITEM_NOT_FOUND", err.Code)
+	assert.Equal(t, "item not found", err.Message)
+	assert.Len(t, err.Details, 1)
+	assert.Equal(t, "iFood API request failed (status 404, code 'ITEM_NOT_FOUND'): item not found", err.Error())
+}
+
+func TestNew_BodyNotJSON(t *testing.T) {
+	err := New("Merchant could not be listed", http.StatusBadGateway, "/v1.0/merchants", []byte("<html>"))
+	assert.Equal(t, "", err.Message)
+	assert.Nil(t, err.Details)
+	assert.Equal(t, "Merchant could not be listed (status 502)", err.Error())
+}
+
+func TestIs_StatusKinds(t *testing.T) {
+	cases := []struct {
+		status int
+		kind   error
+	}{
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrUnauthorized},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnprocessableEntity, ErrValidation},
+	}
+	for _, c := range cases {
+		err := fmt.Errorf("wrapped: %w", New("", c.status, "", nil))
+		assert.True(t, errors.Is(err, c.kind), "status %d", c.status)
+	}
+	assert.Nil(t, New("", http.StatusInternalServerError, "", nil).Kind())
+}
+
+func TestIs_WithKind(t *testing.T) {
+	err := New("Could not authenticate", http.StatusBadRequest, "/oauth/token", nil).WithKind(ErrUnauthorized)
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.False(t, errors.Is(err, ErrNotFound))
+}
+
+func TestAs(t *testing.T) {
+	var err error = New("", http.StatusConflict, "/v1.0/orders", []byte(`{"message":"already dispatched"}`))
+	var apiErr *APIError
+	assert.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.Status)
+	assert.Equal(t, "already dispatched", apiErr.Message)
+}
+
+func TestNew_OAuthBody(t *testing.T) {
+	body := []byte(`{"error":"invalid_grant","error_description":"refresh token revoked"}`)
+	err := New("Could not authenticate", http.StatusBadRequest, "/authentication/v1.0/oauth/token", body)
+	assert.Equal(t, "invalid_grant", err.Code)
+	assert.Equal(t, "refresh token revoked", err.Message)
+}
@@ -3,12 +3,19 @@ package container
 const (
 	// EnvProduction is the production env
 	EnvProduction = iota
-	// EnvDevelopment is the dev env
+	// EnvDevelopment is the local env, every service points at a
+	// stand-in server listening on urlLocal
 	EnvDevelopment
-	// EnvSandBox is the local/sandbox env
+	// EnvSandBox is the homologation env, iFood has no public
+	// homologation host so its base url must be set explicitly
 	EnvSandBox
 
+	// EnvHomologation is an alias of EnvSandBox
+	EnvHomologation = EnvSandBox
+	// EnvLocal is an alias of EnvDevelopment
+	EnvLocal = EnvDevelopment
+
 	urlProduction   = "https://pos-api.ifood.com.br"
 	v2urlProduction = "https://merchant-api.ifood.com.br"
-	urlSandbox      = "https://pos-api.ifood.com.br"
+	urlLocal        = "http://localhost:8080"
 )
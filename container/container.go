@@ -1,20 +1,19 @@
 package container
 
 import (
+	"errors"
 	"net/http"
 	"os"
-	"strconv"
 	"time"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
-	"github.com/arxdsilva/golang-ifood-sdk/mocks"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 	"github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 	"github.com/arxdsilva/golang-ifood-sdk/services/catalog"
 	"github.com/arxdsilva/golang-ifood-sdk/services/events"
 	"github.com/arxdsilva/golang-ifood-sdk/services/merchant"
 	"github.com/arxdsilva/golang-ifood-sdk/services/orders"
-	"github.com/kpango/glg"
 )
 
 // Container is the SDK abstractions holder to facilitate the API manipulation
@@ -23,20 +22,49 @@ type Container struct {
 	v2              bool
 	timeout         time.Duration
 	httpadapter     adapters.Http
+	adapters        map[string]adapters.Http
+	rateLimiter     *httpadapter.RateLimiter
+	log             logger.Logger
+	clientId        string
+	clientSecret    string
+	client          httpadapter.HTTPClient
+	transport       http.RoundTripper
+	timeoutSet      bool
+	override        Environment
+	userAgent       string
+	retry           *httpadapter.RetryPolicy
+	tokenStore      authentication.TokenStore
+	multiMerchant   bool
 	AuthService     authentication.Service
+	Credentials     *authentication.Manager // set by WithMultiMerchant
 	MerchantService merchant.Service
 	CatalogService  catalog.Service
 	EventsService   events.Service
 	OrdersService   orders.Service
 }
 
+// legacyLog reports the fallbacks of Create and CreateFromEnvs,
+// they have no error to return
+var legacyLog = logger.NewText(nil, logger.LevelWarn)
+
 // New returns a new container
 func New(env int, timeout time.Duration, v2 bool) *Container {
 	return &Container{env: env, timeout: timeout, v2: v2}
 }
 
+// Create returns a container with every service set, use
+// NewWithOptions to customize it and get the validation errors.
+// EnvSandBox keeps using the production hosts, an unknown env
+// is logged and falls back to EnvProduction
 func Create(clientId, clientSecret string, env int, v2 bool) (c *Container) {
+	if _, err := EnvironmentOf(env, v2); err != nil {
+		legacyLog.Error("[SDK] (container::Create) falling back to production", logger.F("env", env), logger.Err(err))
+		env = EnvProduction
+	}
 	c = New(env, time.Minute, v2)
+	if env == EnvSandBox {
+		c.override = AllServices("homologation", Production(v2).Auth)
+	}
 	c.GetHttpAdapter()
 	c.GetAuthenticationService(clientId, clientSecret)
 	c.GetMerchantService()
@@ -47,119 +75,203 @@ func Create(clientId, clientSecret string, env int, v2 bool) (c *Container) {
 }
 
 // CreateFromEnvs creates a new instance of the container struct
-// from envs
+// from envs, invalid ones are logged and fall back to production,
+// use NewFromEnvs to get the error
 // 		"IFOOD_CLIENT_ID"
 // 		"IFOOD_CLIENT_SECRET"
 //		"IFOOD_ENV" (default to Production)
+//		"IFOOD_CONFIG_FILE" and the IFOOD_BASE_URL family, see WithEnvs
 //		always uses the api v2
 func CreateFromEnvs() (c *Container) {
-	clientID := os.Getenv("IFOOD_CLIENT_ID")
-	clientSecret := os.Getenv("IFOOD_CLIENT_SECRET")
-	env, _ := strconv.Atoi(os.Getenv("IFOOD_ENV"))
-	return Create(clientID, clientSecret, env, true)
+	c, err := NewFromEnvs()
+	if err != nil {
+		legacyLog.Error("[SDK] (container::CreateFromEnvs) invalid envs, falling back to production", logger.Err(err))
+		return Create(os.Getenv("IFOOD_CLIENT_ID"), os.Getenv("IFOOD_CLIENT_SECRET"), EnvProduction, true)
+	}
+	c.GetAuthenticationService(c.clientId, c.clientSecret)
+	c.GetMerchantService()
+	c.GetCatalogService()
+	c.GetEventsService()
+	c.GetOrdersService()
+	return
+}
+
+// Environment returns the hosts used by the container services,
+// ErrNoBaseURL when a service has none
+func (c *Container) Environment() (Environment, error) {
+	e, err := EnvironmentOf(c.env, c.v2)
+	if err != nil {
+		return e, err
+	}
+	e = e.Merge(c.override)
+	return e, e.complete()
+}
+
+// environment is Environment reporting an unknown env as ErrNoHttpAdapter
+func (c *Container) environment() (Environment, error) {
+	e, err := c.Environment()
+	if errors.Is(err, ErrInvalidEnvironment) {
+		return e, ErrNoHttpAdapter
+	}
+	return e, err
 }
 
-// GetHttpAdapter returns new HTTP adapter according to the env
+// GetHttpAdapter returns new HTTP adapter according to the env,
+// it is the merchant service adapter
 func (c *Container) GetHttpAdapter() adapters.Http {
 	if c.httpadapter != nil {
 		return c.httpadapter
 	}
-	client := &http.Client{
-		Timeout: c.timeout,
+	e, err := c.Environment()
+	if err != nil {
+		return nil
 	}
-	switch c.env {
-	case EnvDevelopment:
-		c.httpadapter = httpadapter.New(new(mocks.HttpClientMock), "")
-	case EnvProduction:
-		if c.v2 {
-			c.httpadapter = httpadapter.New(client, v2urlProduction)
-			return c.httpadapter
-		}
-		c.httpadapter = httpadapter.New(client, urlProduction)
-	case EnvSandBox:
-		if c.v2 {
-			c.httpadapter = httpadapter.New(client, v2urlProduction)
-			return c.httpadapter
+	c.httpadapter = c.adapterFor(e.Merchant)
+	return c.httpadapter
+}
+
+// adapterFor returns the adapter of a host, the adapters share
+// the http client, rate limiter and logger
+func (c *Container) adapterFor(baseURL string) adapters.Http {
+	if a, ok := c.adapters[baseURL]; ok {
+		return a
+	}
+	if c.client == nil {
+		c.client = &http.Client{
+			Timeout:   c.timeout,
+			Transport: c.transport,
 		}
-		c.httpadapter = httpadapter.New(client, urlSandbox)
 	}
-	return c.httpadapter
+	opts := []httpadapter.Option{
+		httpadapter.WithRateLimiter(c.RateLimiter()),
+		httpadapter.WithLogger(c.Logger()),
+	}
+	if c.retry != nil {
+		opts = append(opts, httpadapter.WithRetryPolicy(*c.retry))
+	}
+	if c.userAgent != "" {
+		opts = append(opts, httpadapter.WithUserAgent(c.userAgent))
+	}
+	if c.adapters == nil {
+		c.adapters = make(map[string]adapters.Http)
+	}
+	c.adapters[baseURL] = httpadapter.New(c.client, baseURL, opts...)
+	return c.adapters[baseURL]
+}
+
+// RateLimiter returns the client side limiter shared by every service
+// of the container, use it to tune limits or read its utilization
+func (c *Container) RateLimiter() *httpadapter.RateLimiter {
+	if c.rateLimiter == nil {
+		c.rateLimiter = httpadapter.NewRateLimiter(httpadapter.DefaultLimits(), false)
+	}
+	return c.rateLimiter
+}
+
+// SetLogger plugs l into the container, it must be called before
+// GetHttpAdapter since every service logs through the adapter,
+// tokens and customer PII are redacted before reaching l
+func (c *Container) SetLogger(l logger.Logger) {
+	c.log = logger.Redact(l)
+}
+
+// Logger returns the container logger, nothing is logged by default
+func (c *Container) Logger() logger.Logger {
+	if c.log == nil {
+		c.log = logger.Nop()
+	}
+	return c.log
 }
 
 // Do a start method to instantiate all services instead of each separated
 
-// GetAuthenticationService instantiates an auth service, also adds it to the container
-func (c *Container) GetAuthenticationService(clientId, clientSecret string) authentication.Service {
-	if c.httpadapter == nil {
-		glg.Warn("[GetAuthenticationService]: http adapter is nil, please set it with Container.GetHttpAdapter")
-		return nil
+// GetAuthenticationService instantiates an auth service, also adds it to the container,
+// empty credentials fall back to the ones given with WithCredentials
+func (c *Container) GetAuthenticationService(clientId, clientSecret string) (authentication.Service, error) {
+	if c.AuthService != nil {
+		return c.AuthService, nil
 	}
-	if c.AuthService == nil {
-		c.AuthService = authentication.New(c.GetHttpAdapter(), clientId, clientSecret, c.v2)
+	if clientId == "" && clientSecret == "" {
+		clientId, clientSecret = c.clientId, c.clientSecret
+	}
+	e, err := c.environment()
+	if err != nil {
+		return nil, err
 	}
-	return c.AuthService
+	var opts []authentication.Option
+	if c.tokenStore != nil {
+		opts = append(opts, authentication.WithTokenStore(c.tokenStore, ""))
+	}
+	if c.multiMerchant {
+		c.Credentials = authentication.NewManager(c.adapterFor(e.Auth), clientId, clientSecret, c.tokenStore)
+		c.AuthService = c.Credentials
+		return c.AuthService, nil
+	}
+	c.AuthService = authentication.New(c.adapterFor(e.Auth), clientId, clientSecret, c.v2, opts...)
+	return c.AuthService, nil
 }
 
 // GetMerchantService instantiates an merchant service, also adds it to the container
-func (c *Container) GetMerchantService() merchant.Service {
-	if c.httpadapter == nil {
-		glg.Warn("[GetMerchantService]: http adapter is nil, please set it with Container.GetHttpAdapter")
-		return nil
+func (c *Container) GetMerchantService() (merchant.Service, error) {
+	if c.MerchantService != nil {
+		return c.MerchantService, nil
 	}
-	if c.AuthService == nil {
-		glg.Warn("[GetMerchantService]: please set the authentication service")
-		return nil
-	}
-	if c.MerchantService == nil {
-		c.MerchantService = merchant.New(c.GetHttpAdapter(), c.AuthService)
+	adapter, err := c.serviceDeps(func(e Environment) string { return e.Merchant })
+	if err != nil {
+		return nil, err
 	}
-	return c.MerchantService
+	c.MerchantService = merchant.New(adapter, c.AuthService)
+	return c.MerchantService, nil
 }
 
 // GetCatalogService instantiates an catalog service, also adds it to the container
-func (c *Container) GetCatalogService() catalog.Service {
-	if c.httpadapter == nil {
-		glg.Warn("[GetCatalogService]: http adapter is nil, please set it with Container.GetHttpAdapter")
-		return nil
+func (c *Container) GetCatalogService() (catalog.Service, error) {
+	if c.CatalogService != nil {
+		return c.CatalogService, nil
 	}
-	if c.AuthService == nil {
-		glg.Warn("[GetCatalogService]: please set the authentication service")
-		return nil
+	adapter, err := c.serviceDeps(func(e Environment) string { return e.Catalog })
+	if err != nil {
+		return nil, err
 	}
-	if c.CatalogService == nil {
-		c.CatalogService = catalog.New(c.GetHttpAdapter(), c.AuthService)
-	}
-	return c.CatalogService
+	c.CatalogService = catalog.New(adapter, c.AuthService)
+	return c.CatalogService, nil
 }
 
 // GetEventsService instantiates an events service, also adds it to the container
-func (c *Container) GetEventsService() events.Service {
-	if c.httpadapter == nil {
-		glg.Warn("[GetEventsService]: http adapter is nil, please set it with Container.GetHttpAdapter")
-		return nil
-	}
-	if c.AuthService == nil {
-		glg.Warn("[GetEventsService]: please set the authentication service")
-		return nil
+func (c *Container) GetEventsService() (events.Service, error) {
+	if c.EventsService != nil {
+		return c.EventsService, nil
 	}
-	if c.EventsService == nil {
-		c.EventsService = events.New(c.GetHttpAdapter(), c.AuthService, true)
+	adapter, err := c.serviceDeps(func(e Environment) string { return e.Events })
+	if err != nil {
+		return nil, err
 	}
-	return c.EventsService
+	c.EventsService = events.New(adapter, c.AuthService, true)
+	return c.EventsService, nil
 }
 
 // GetOrdersService instantiates an orders service, also adds it to the container
-func (c *Container) GetOrdersService() orders.Service {
-	if c.httpadapter == nil {
-		glg.Warn("[GetOrdersService]: http adapter is nil, please set it with Container.GetHttpAdapter")
-		return nil
+func (c *Container) GetOrdersService() (orders.Service, error) {
+	if c.OrdersService != nil {
+		return c.OrdersService, nil
 	}
-	if c.AuthService == nil {
-		glg.Warn("[GetOrdersService]: please set the authentication service")
-		return nil
+	adapter, err := c.serviceDeps(func(e Environment) string { return e.Order })
+	if err != nil {
+		return nil, err
 	}
-	if c.OrdersService == nil {
-		c.OrdersService = orders.New(c.GetHttpAdapter(), c.AuthService)
+	c.OrdersService = orders.New(adapter, c.AuthService)
+	return c.OrdersService, nil
+}
+
+// serviceDeps returns the adapter of the service host
+// once the authentication service is set
+func (c *Container) serviceDeps(host func(Environment) string) (adapters.Http, error) {
+	e, err := c.environment()
+	if err != nil {
+		return nil, err
+	}
+	if c.AuthService == nil {
+		return nil, ErrNoAuthService
 	}
-	return c.OrdersService
+	return c.adapterFor(host(e)), nil
 }
@@ -0,0 +1,174 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment holds the API host of each service, the services
+// sharing a host also share an http adapter
+type Environment struct {
+	// Name is "production", "homologation" or "local"
+	Name     string `json:"environment"`
+	Auth     string `json:"auth"`
+	Merchant string `json:"merchant"`
+	Order    string `json:"order"`
+	Events   string `json:"events"`
+	Catalog  string `json:"catalog"`
+}
+
+var envNames = map[string]int{
+	"production":   EnvProduction,
+	"homologation": EnvHomologation,
+	"sandbox":      EnvSandBox,
+	"local":        EnvLocal,
+	"development":  EnvDevelopment,
+}
+
+// Production returns the production hosts of the v1 or v2 API
+func Production(v2 bool) Environment {
+	if v2 {
+		return AllServices("production", v2urlProduction)
+	}
+	return AllServices("production", urlProduction)
+}
+
+// Homologation returns the homologation environment, it has no hosts
+// since iFood gives each integration its own, set them with the
+// WithBaseURL family of options
+func Homologation() Environment {
+	return Environment{Name: "homologation"}
+}
+
+// Local returns an environment served by a local stand-in of the
+// iFood API, http://localhost:8080 when baseURL is empty
+func Local(baseURL string) Environment {
+	if baseURL == "" {
+		baseURL = urlLocal
+	}
+	return AllServices("local", baseURL)
+}
+
+// AllServices returns an environment where every service uses baseURL
+func AllServices(name, baseURL string) Environment {
+	return Environment{Name: name, Auth: baseURL, Merchant: baseURL, Order: baseURL, Events: baseURL, Catalog: baseURL}
+}
+
+// EnvironmentOf returns the hosts of one of the Env constants
+func EnvironmentOf(env int, v2 bool) (Environment, error) {
+	switch env {
+	case EnvProduction:
+		return Production(v2), nil
+	case EnvSandBox:
+		return Homologation(), nil
+	case EnvDevelopment:
+		return Local(""), nil
+	}
+	return Environment{}, ErrInvalidEnvironment
+}
+
+// ParseEnv converts an environment name, such as "homologation",
+// or the number of an Env constant into the constant
+func ParseEnv(value string) (int, error) {
+	if env, ok := envNames[strings.ToLower(strings.TrimSpace(value))]; ok {
+		return env, nil
+	}
+	env, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, ErrInvalidEnvironment
+	}
+	if _, err = EnvironmentOf(env, true); err != nil {
+		return 0, err
+	}
+	return env, nil
+}
+
+// LoadEnvironment reads a JSON config file, the "baseUrl" key sets
+// every service, the service keys override it
+//
+//	{"environment": "local", "baseUrl": "http://localhost:9000", "events": "http://localhost:9001"}
+func LoadEnvironment(path string) (e Environment, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var file struct {
+		Environment
+		BaseURL string `json:"baseUrl"`
+	}
+	if err = json.Unmarshal(data, &file); err != nil {
+		return e, fmt.Errorf("%w: config file '%s': %s", ErrInvalidOption, path, err.Error())
+	}
+	e = AllServices(file.Name, file.BaseURL).Merge(file.Environment)
+	return e, e.validate()
+}
+
+// EnvironmentFromEnvs reads the IFOOD_BASE_URL family of env vars,
+// IFOOD_BASE_URL sets every service and the per service vars
+// override it
+//
+//	"IFOOD_AUTH_BASE_URL"
+//	"IFOOD_MERCHANT_BASE_URL"
+//	"IFOOD_ORDER_BASE_URL"
+//	"IFOOD_EVENTS_BASE_URL"
+//	"IFOOD_CATALOG_BASE_URL"
+func EnvironmentFromEnvs() (Environment, error) {
+	e := AllServices("", os.Getenv("IFOOD_BASE_URL")).Merge(Environment{
+		Auth:     os.Getenv("IFOOD_AUTH_BASE_URL"),
+		Merchant: os.Getenv("IFOOD_MERCHANT_BASE_URL"),
+		Order:    os.Getenv("IFOOD_ORDER_BASE_URL"),
+		Events:   os.Getenv("IFOOD_EVENTS_BASE_URL"),
+		Catalog:  os.Getenv("IFOOD_CATALOG_BASE_URL"),
+	})
+	return e, e.validate()
+}
+
+// Merge returns e with the non empty hosts of o
+func (e Environment) Merge(o Environment) Environment {
+	pick := func(current, override string) string {
+		if override != "" {
+			return override
+		}
+		return current
+	}
+	return Environment{
+		Name:     pick(e.Name, o.Name),
+		Auth:     pick(e.Auth, o.Auth),
+		Merchant: pick(e.Merchant, o.Merchant),
+		Order:    pick(e.Order, o.Order),
+		Events:   pick(e.Events, o.Events),
+		Catalog:  pick(e.Catalog, o.Catalog),
+	}
+}
+
+// complete returns ErrNoBaseURL when a service has no host
+func (e Environment) complete() error {
+	for _, host := range []string{e.Auth, e.Merchant, e.Order, e.Events, e.Catalog} {
+		if host == "" {
+			return fmt.Errorf("%w: environment '%s'", ErrNoBaseURL, e.Name)
+		}
+	}
+	return nil
+}
+
+func (e Environment) validate() error {
+	if e.Name != "" {
+		if _, ok := envNames[strings.ToLower(e.Name)]; !ok {
+			return fmt.Errorf("%w: environment '%s'", ErrInvalidEnvironment, e.Name)
+		}
+	}
+	for _, host := range []string{e.Auth, e.Merchant, e.Order, e.Events, e.Catalog} {
+		if host == "" {
+			continue
+		}
+		if u, err := url.Parse(host); err != nil || u.Scheme == "" || u.Host == "" {
+			return invalidOption(fmt.Sprintf("base url '%s' should be absolute", host))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package container
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setEnvs(t *testing.T, envs map[string]string) {
+	for k, v := range envs {
+		os.Setenv(k, v)
+	}
+	t.Cleanup(func() {
+		for k := range envs {
+			os.Unsetenv(k)
+		}
+	})
+}
+
+func TestEnvironmentOf(t *testing.T) {
+	e, err := EnvironmentOf(EnvProduction, true)
+	assert.Nil(t, err)
+	assert.Equal(t, AllServices("production", v2urlProduction), e)
+	e, err = EnvironmentOf(EnvHomologation, false)
+	assert.Nil(t, err)
+	assert.Equal(t, Homologation(), e)
+	assert.True(t, errors.Is(e.complete(), ErrNoBaseURL))
+	e, err = EnvironmentOf(EnvLocal, true)
+	assert.Nil(t, err)
+	assert.Equal(t, urlLocal, e.Events)
+	_, err = EnvironmentOf(42, true)
+	assert.Equal(t, ErrInvalidEnvironment, err)
+}
+
+func TestNewWithOptions_Homologation(t *testing.T) {
+	_, err := NewWithOptions(WithEnvironment(EnvHomologation))
+	assert.True(t, errors.Is(err, ErrNoBaseURL))
+	c, err := NewWithOptions(WithEnvironment(EnvHomologation), WithBaseURL("https://homologation.example.com"))
+	assert.Nil(t, err)
+	e, err := c.Environment()
+	assert.Nil(t, err)
+	assert.Equal(t, "homologation", e.Name)
+	assert.Equal(t, "https://homologation.example.com", e.Order)
+}
+
+func TestParseEnv(t *testing.T) {
+	for value, want := range map[string]int{"production": EnvProduction, "Homologation": EnvSandBox, "local": EnvDevelopment, "2": EnvSandBox} {
+		env, err := ParseEnv(value)
+		assert.Nil(t, err, value)
+		assert.Equal(t, want, env, value)
+	}
+	_, err := ParseEnv("staging")
+	assert.Equal(t, ErrInvalidEnvironment, err)
+}
+
+func TestLoadEnvironment(t *testing.T) {
+	f, err := ioutil.TempFile("", "ifood-env-*.json")
+	assert.Nil(t, err)
+	defer os.Remove(f.Name())
+	f.WriteString(`{"environment": "local", "baseUrl": "http://localhost:9000", "events": "http://localhost:9001"}`)
+	f.Close()
+	e, err := LoadEnvironment(f.Name())
+	assert.Nil(t, err)
+	assert.Equal(t, "local", e.Name)
+	assert.Equal(t, "http://localhost:9000", e.Auth)
+	assert.Equal(t, "http://localhost:9001", e.Events)
+	_, err = LoadEnvironment(f.Name() + ".missing")
+	assert.NotNil(t, err)
+}
+
+func TestEnvironmentFromEnvs(t *testing.T) {
+	setEnvs(t, map[string]string{
+		"IFOOD_BASE_URL":       "http://localhost:9000",
+		"IFOOD_ORDER_BASE_URL": "http://localhost:9002",
+	})
+	e, err := EnvironmentFromEnvs()
+	assert.Nil(t, err)
+	assert.Equal(t, "http://localhost:9000", e.Merchant)
+	assert.Equal(t, "http://localhost:9002", e.Order)
+	os.Setenv("IFOOD_CATALOG_BASE_URL", "catalog")
+	defer os.Unsetenv("IFOOD_CATALOG_BASE_URL")
+	_, err = EnvironmentFromEnvs()
+	assert.NotNil(t, err)
+}
+
+func TestNewFromEnvs_ServiceHosts(t *testing.T) {
+	var hits []string
+	server := func(name string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hits = append(hits, name)
+			w.Write([]byte(`[]`))
+		}))
+	}
+	merchants, orders := server("merchant"), server("order")
+	defer merchants.Close()
+	defer orders.Close()
+	setEnvs(t, map[string]string{
+		"IFOOD_CLIENT_ID":      "id",
+		"IFOOD_CLIENT_SECRET":  "secret",
+		"IFOOD_ENV":            "local",
+		"IFOOD_BASE_URL":       merchants.URL,
+		"IFOOD_ORDER_BASE_URL": orders.URL,
+	})
+	c, err := NewFromEnvs()
+	assert.Nil(t, err)
+	e, err := c.Environment()
+	assert.Nil(t, err)
+	assert.Equal(t, "local", e.Name)
+	assert.Equal(t, orders.URL, e.Order)
+	c.adapterFor(e.Merchant).DoRequest(http.MethodGet, "/", nil, nil)
+	c.adapterFor(e.Order).DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Equal(t, []string{"merchant", "order"}, hits)
+	assert.NotNil(t, CreateFromEnvs().OrdersService)
+	os.Setenv("IFOOD_ENV", "staging")
+	c = CreateFromEnvs()
+	assert.NotNil(t, c.OrdersService)
+	e, err = c.Environment()
+	assert.Nil(t, err)
+	assert.Equal(t, AllServices("production", v2urlProduction), e)
+}
+
+func TestCreate_LegacyEnvironments(t *testing.T) {
+	c := Create("id", "secret", EnvSandBox, true)
+	assert.NotNil(t, c.AuthService)
+	assert.NotNil(t, c.OrdersService)
+	e, err := c.Environment()
+	assert.Nil(t, err)
+	assert.Equal(t, AllServices("homologation", v2urlProduction), e)
+	c = Create("id", "secret", 42, false)
+	assert.NotNil(t, c.OrdersService)
+	e, err = c.Environment()
+	assert.Nil(t, err)
+	assert.Equal(t, Production(false), e)
+}
@@ -0,0 +1,18 @@
+package container
+
+import "errors"
+
+var (
+	// ErrNoHttpAdapter the container environment has no http adapter
+	ErrNoHttpAdapter = errors.New("http adapter is nil, the container environment is unknown")
+	// ErrNoAuthService the authentication service was not set
+	ErrNoAuthService = errors.New("authentication service is nil, set it with Container.GetAuthenticationService")
+	// ErrNoCredentials the client id or secret were not specified
+	ErrNoCredentials = errors.New("client id and client secret should be specified")
+	// ErrInvalidEnvironment the environment is not one of the Env constants
+	ErrInvalidEnvironment = errors.New("environment should be production, homologation or local")
+	// ErrNoBaseURL the environment has no default host and none was set
+	ErrNoBaseURL = errors.New("environment has no default host, set the base url with WithBaseURL, a config file or IFOOD_BASE_URL")
+	// ErrInvalidOption an option was given an invalid value
+	ErrInvalidOption = errors.New("invalid container option")
+)
@@ -0,0 +1,264 @@
+package container
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+	"github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+)
+
+// Option configures a Container built with NewWithOptions
+type Option func(*Container) error
+
+// NewWithOptions returns a container for the production v2 API
+// customized by opts, when credentials are given every service
+// is wired right away, otherwise use the Get*Service methods
+func NewWithOptions(opts ...Option) (c *Container, err error) {
+	c = New(EnvProduction, time.Minute, true)
+	for _, opt := range opts {
+		if err = opt(c); err != nil {
+			return nil, err
+		}
+	}
+	if c.client != nil && (c.transport != nil || c.timeoutSet) {
+		return nil, invalidOption("WithTransport and WithTimeout cannot be combined with WithHTTPClient")
+	}
+	if c.multiMerchant && !c.v2 {
+		return nil, invalidOption("WithMultiMerchant needs the V2 API")
+	}
+	if _, err = c.Environment(); err != nil {
+		return nil, err
+	}
+	if c.clientId == "" && c.clientSecret == "" {
+		return
+	}
+	if _, err = c.GetAuthenticationService(c.clientId, c.clientSecret); err != nil {
+		return nil, err
+	}
+	if _, err = c.GetMerchantService(); err != nil {
+		return nil, err
+	}
+	if _, err = c.GetCatalogService(); err != nil {
+		return nil, err
+	}
+	if _, err = c.GetEventsService(); err != nil {
+		return nil, err
+	}
+	if _, err = c.GetOrdersService(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// NewFromEnvs returns a container configured by WithEnvs and opts
+func NewFromEnvs(opts ...Option) (*Container, error) {
+	return NewWithOptions(append([]Option{WithEnvs()}, opts...)...)
+}
+
+// WithEnvironment selects one of the Env constants, EnvProduction by default
+func WithEnvironment(env int) Option {
+	return func(c *Container) error {
+		if _, err := EnvironmentOf(env, c.v2); err != nil {
+			return err
+		}
+		c.env = env
+		return nil
+	}
+}
+
+// WithV2 selects the iFood API version, v2 by default
+func WithV2(v2 bool) Option {
+	return func(c *Container) error {
+		c.v2 = v2
+		return nil
+	}
+}
+
+// WithCredentials sets the client id and secret of the authentication service
+func WithCredentials(clientId, clientSecret string) Option {
+	return func(c *Container) error {
+		if clientId == "" || clientSecret == "" {
+			return ErrNoCredentials
+		}
+		c.clientId, c.clientSecret = clientId, clientSecret
+		return nil
+	}
+}
+
+// WithHTTPClient replaces the default *http.Client
+func WithHTTPClient(client httpadapter.HTTPClient) Option {
+	return func(c *Container) error {
+		if client == nil {
+			return invalidOption("http client is nil")
+		}
+		c.client = client
+		return nil
+	}
+}
+
+// WithTransport sets the transport of the default *http.Client
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Container) error {
+		if transport == nil {
+			return invalidOption("transport is nil")
+		}
+		c.transport = transport
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of the default *http.Client, one minute by default
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Container) error {
+		if timeout <= 0 {
+			return invalidOption(fmt.Sprintf("timeout '%v' should be positive", timeout))
+		}
+		c.timeout, c.timeoutSet = timeout, true
+		return nil
+	}
+}
+
+// WithBaseURL replaces the environment host of every service
+func WithBaseURL(baseURL string) Option {
+	return func(c *Container) error {
+		u, err := url.Parse(baseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return invalidOption(fmt.Sprintf("base url '%s' should be absolute", baseURL))
+		}
+		c.override = c.override.Merge(AllServices("", baseURL))
+		return nil
+	}
+}
+
+// WithEnvironmentConfig overrides the environment hosts with the non
+// empty hosts of e, a non empty e.Name also selects the environment
+func WithEnvironmentConfig(e Environment) Option {
+	return func(c *Container) error {
+		if err := e.validate(); err != nil {
+			return err
+		}
+		if e.Name != "" {
+			c.env = envNames[strings.ToLower(e.Name)]
+		}
+		e.Name = ""
+		c.override = c.override.Merge(e)
+		return nil
+	}
+}
+
+// WithConfigFile applies a JSON config file, see LoadEnvironment
+func WithConfigFile(path string) Option {
+	return func(c *Container) error {
+		e, err := LoadEnvironment(path)
+		if err != nil {
+			return err
+		}
+		return WithEnvironmentConfig(e)(c)
+	}
+}
+
+// WithEnvs reads the container settings from env vars, the
+// IFOOD_CONFIG_FILE is applied first and the other vars override it
+//
+//	"IFOOD_CLIENT_ID" and "IFOOD_CLIENT_SECRET"
+//	"IFOOD_ENV", a name such as "homologation" or an Env constant
+//	"IFOOD_CONFIG_FILE"
+//	"IFOOD_BASE_URL" and the per service vars, see EnvironmentFromEnvs
+func WithEnvs() Option {
+	return func(c *Container) (err error) {
+		c.clientId = os.Getenv("IFOOD_CLIENT_ID")
+		c.clientSecret = os.Getenv("IFOOD_CLIENT_SECRET")
+		if path := os.Getenv("IFOOD_CONFIG_FILE"); path != "" {
+			if err = WithConfigFile(path)(c); err != nil {
+				return
+			}
+		}
+		if value := os.Getenv("IFOOD_ENV"); value != "" {
+			if c.env, err = ParseEnv(value); err != nil {
+				return
+			}
+		}
+		e, err := EnvironmentFromEnvs()
+		if err != nil {
+			return
+		}
+		c.override = c.override.Merge(e)
+		return
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent to the API
+func WithUserAgent(userAgent string) Option {
+	return func(c *Container) error {
+		c.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithLogger plugs l into the container, see Container.SetLogger
+func WithLogger(l logger.Logger) Option {
+	return func(c *Container) error {
+		if l == nil {
+			return invalidOption("logger is nil")
+		}
+		c.SetLogger(l)
+		return nil
+	}
+}
+
+// WithRetry replaces httpadapter.DefaultRetryPolicy
+func WithRetry(policy httpadapter.RetryPolicy) Option {
+	return func(c *Container) error {
+		if policy.MaxAttempts < 1 {
+			return invalidOption("retry policy needs at least one attempt")
+		}
+		c.retry = &policy
+		return nil
+	}
+}
+
+// WithTokenStore persists the V2 credentials under the client id,
+// so restarts resume from the stored refresh token
+func WithTokenStore(store authentication.TokenStore) Option {
+	return func(c *Container) error {
+		if store == nil {
+			return invalidOption("token store is nil")
+		}
+		c.tokenStore = store
+		return nil
+	}
+}
+
+// WithMultiMerchant uses an authentication.Manager as the auth service,
+// merchant, catalog and orders calls use the token of the merchant
+// linked with Credentials.Link, the token store keeps every merchant
+func WithMultiMerchant() Option {
+	return func(c *Container) error {
+		c.multiMerchant = true
+		return nil
+	}
+}
+
+// WithRateLimit replaces httpadapter.DefaultLimits, a fail fast limiter
+// returns httpadapter.ErrRateLimited instead of waiting for a token
+func WithRateLimit(limits map[httpadapter.Family]httpadapter.Limit, failFast bool) Option {
+	return func(c *Container) error {
+		for family, limit := range limits {
+			if limit.Rate < 0 || limit.Per < 0 || limit.Burst < 0 {
+				return invalidOption(fmt.Sprintf("rate limit of '%s' should not be negative", family))
+			}
+		}
+		c.rateLimiter = httpadapter.NewRateLimiter(limits, failFast)
+		return nil
+	}
+}
+
+func invalidOption(reason string) error {
+	return fmt.Errorf("%w: %s", ErrInvalidOption, reason)
+}
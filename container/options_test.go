@@ -0,0 +1,138 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithOptions_WiresServices(t *testing.T) {
+	var agent string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			agent = r.UserAgent()
+			w.Write([]byte(`[]`))
+		}),
+	)
+	defer ts.Close()
+	c, err := NewWithOptions(
+		WithCredentials("id", "secret"),
+		WithBaseURL(ts.URL),
+		WithUserAgent("my-pos/1.0"),
+		WithRetry(httpadapter.NoRetry()),
+		WithRateLimit(nil, false),
+	)
+	assert.Nil(t, err)
+	assert.NotNil(t, c.AuthService)
+	assert.NotNil(t, c.MerchantService)
+	assert.NotNil(t, c.CatalogService)
+	assert.NotNil(t, c.EventsService)
+	assert.NotNil(t, c.OrdersService)
+	_, status, err := c.GetHttpAdapter().DoRequest(http.MethodGet, "/", nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "my-pos/1.0", agent)
+}
+
+func TestNewWithOptions_NoCredentials(t *testing.T) {
+	c, err := NewWithOptions()
+	assert.Nil(t, err)
+	assert.Nil(t, c.AuthService)
+	_, err = c.GetOrdersService()
+	assert.Equal(t, ErrNoAuthService, err)
+	auth, err := c.GetAuthenticationService("id", "secret")
+	assert.Nil(t, err)
+	assert.NotNil(t, auth)
+	orders, err := c.GetOrdersService()
+	assert.Nil(t, err)
+	assert.NotNil(t, orders)
+}
+
+func TestNewWithOptions_Invalid(t *testing.T) {
+	cases := map[string]Option{
+		"env":         WithEnvironment(42),
+		"credentials": WithCredentials("id", ""),
+		"client":      WithHTTPClient(nil),
+		"transport":   WithTransport(nil),
+		"timeout":     WithTimeout(0),
+		"base url":    WithBaseURL("merchant-api"),
+		"logger":      WithLogger(nil),
+		"retry":       WithRetry(httpadapter.RetryPolicy{}),
+		"token store": WithTokenStore(nil),
+		"rate limit":  WithRateLimit(map[httpadapter.Family]httpadapter.Limit{httpadapter.FamilyOrders: {Rate: -1}}, false),
+	}
+	for name, opt := range cases {
+		c, err := NewWithOptions(opt)
+		assert.Nil(t, c, name)
+		assert.NotNil(t, err, name)
+	}
+	_, err := NewWithOptions(WithHTTPClient(http.DefaultClient), WithTimeout(time.Second))
+	assert.True(t, errors.Is(err, ErrInvalidOption))
+}
+
+func TestGetServices_UnknownEnv(t *testing.T) {
+	c := New(42, time.Minute, true)
+	_, err := c.GetAuthenticationService("id", "secret")
+	assert.Equal(t, ErrNoHttpAdapter, err)
+	_, err = c.GetMerchantService()
+	assert.Equal(t, ErrNoHttpAdapter, err)
+}
+
+func TestGetServices_NoBaseURL(t *testing.T) {
+	c := New(EnvHomologation, time.Minute, true)
+	_, err := c.GetAuthenticationService("id", "secret")
+	assert.True(t, errors.Is(err, ErrNoBaseURL))
+	c.AuthService = &authentication.AuthMock{}
+	_, err = c.GetOrdersService()
+	assert.True(t, errors.Is(err, ErrNoBaseURL))
+}
+
+func TestNewWithOptions_MultiMerchant(t *testing.T) {
+	tokens := map[string]string{"code-a": "token-a", "code-b": "token-b"}
+	var got []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/authentication/v1.0/oauth/token" {
+				r.ParseForm()
+				code := r.PostForm.Get("authorizationCode")
+				fmt.Fprintf(w, `{"accessToken":"%s","refreshToken":"refresh-%s","expiresIn":3600}`, tokens[code], code)
+				return
+			}
+			got = append(got, r.Header.Get("Authorization"))
+			if r.Method == http.MethodPost {
+				w.WriteHeader(http.StatusAccepted)
+				return
+			}
+			w.Write([]byte(`[]`))
+		}),
+	)
+	defer ts.Close()
+	c, err := NewWithOptions(WithCredentials("id", "secret"), WithBaseURL(ts.URL), WithMultiMerchant())
+	assert.Nil(t, err)
+	assert.Equal(t, c.Credentials, c.AuthService)
+	ctx := context.Background()
+	assert.Nil(t, c.Credentials.Link(ctx, "merchant-a", "code-a", "verifier"))
+	assert.Nil(t, c.Credentials.Link(ctx, "merchant-b", "code-b", "verifier"))
+	_, err = c.MerchantService.AvailabilityCtx(ctx, "merchant-b")
+	assert.Nil(t, err)
+	_, err = c.CatalogService.ListAllV2Ctx(ctx, "merchant-a")
+	assert.Nil(t, err)
+	err = c.OrdersService.SetConfirmStatusCtx(authentication.WithMerchant(ctx, "merchant-b"), "order")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Bearer token-b", "Bearer token-a", "Bearer token-b"}, got)
+	_, err = c.MerchantService.AvailabilityCtx(ctx, "merchant-c")
+	assert.ErrorIs(t, err, authentication.ErrUnknownMerchant)
+	err = c.OrdersService.SetConfirmStatusCtx(ctx, "order")
+	assert.ErrorIs(t, err, authentication.ErrMerchantRequired)
+
+	_, err = NewWithOptions(WithV2(false), WithMultiMerchant())
+	assert.True(t, errors.Is(err, ErrInvalidOption))
+}
@@ -4,14 +4,11 @@ go 1.17
 
 require (
 	github.com/gofrs/uuid v4.2.0+incompatible
-	github.com/kpango/glg v1.6.10
 	github.com/stretchr/testify v1.7.1
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/goccy/go-json v0.9.4 // indirect
-	github.com/kpango/fastime v1.1.4 // indirect
 	github.com/kr/pretty v0.1.0 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.1.0 // indirect
@@ -0,0 +1,32 @@
+package logger
+
+// KV is the key/value logging API shared by log/slog's *slog.Logger
+// and most structured loggers, the args alternate keys and values
+type KV interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+type kvLogger struct {
+	kv KV
+}
+
+// FromKV adapts a key/value logger, e.g. FromKV(slog.Default())
+func FromKV(kv KV) Logger {
+	return kvLogger{kv: kv}
+}
+
+func (l kvLogger) Debug(msg string, fields ...Field) { l.kv.Debug(msg, args(fields)...) }
+func (l kvLogger) Info(msg string, fields ...Field)  { l.kv.Info(msg, args(fields)...) }
+func (l kvLogger) Warn(msg string, fields ...Field)  { l.kv.Warn(msg, args(fields)...) }
+func (l kvLogger) Error(msg string, fields ...Field) { l.kv.Error(msg, args(fields)...) }
+
+func args(fields []Field) []interface{} {
+	kv := make([]interface{}, 0, len(fields)*2)
+	for _, f := range fields {
+		kv = append(kv, f.Key, f.Value)
+	}
+	return kv
+}
@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type kvRecorder struct {
+	level string
+	msg   string
+	args  []interface{}
+}
+
+func (r *kvRecorder) record(level, msg string, args []interface{}) {
+	r.level, r.msg, r.args = level, msg, args
+}
+
+func (r *kvRecorder) Debug(msg string, args ...interface{}) { r.record("debug", msg, args) }
+func (r *kvRecorder) Info(msg string, args ...interface{})  { r.record("info", msg, args) }
+func (r *kvRecorder) Warn(msg string, args ...interface{})  { r.record("warn", msg, args) }
+func (r *kvRecorder) Error(msg string, args ...interface{}) { r.record("error", msg, args) }
+
+func TestFromKV(t *testing.T) {
+	rec := &kvRecorder{}
+	l := FromKV(rec)
+	l.Warn("[SDK] polling", F("status", 429), F("merchant", "m-1"))
+	assert.Equal(t, "warn", rec.level)
+	assert.Equal(t, "[SDK] polling", rec.msg)
+	assert.Equal(t, []interface{}{"status", 429, "merchant", "m-1"}, rec.args)
+	l.Debug("[SDK] no fields")
+	assert.Equal(t, "debug", rec.level)
+	assert.Empty(t, rec.args)
+}
@@ -0,0 +1,58 @@
+// Package logger is the logging abstraction used across the SDK,
+// nothing is logged unless a Logger is plugged into the container
+package logger
+
+type (
+	// Logger receives the SDK logs as a message plus structured fields
+	Logger interface {
+		Debug(msg string, fields ...Field)
+		Info(msg string, fields ...Field)
+		Warn(msg string, fields ...Field)
+		Error(msg string, fields ...Field)
+	}
+
+	// Field is a structured log attribute
+	Field struct {
+		Key   string
+		Value interface{}
+	}
+
+	// Provider is implemented by SDK components carrying a Logger,
+	// such as the http adapter
+	Provider interface {
+		Logger() Logger
+	}
+
+	nop struct{}
+)
+
+// F returns a Field
+func F(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns the Field holding err under the "error" key
+func Err(err error) Field {
+	return Field{Key: "error", Value: err}
+}
+
+// Nop returns a Logger that discards everything
+func Nop() Logger {
+	return nop{}
+}
+
+// Of returns the Logger carried by v when it is a Provider,
+// or a Nop Logger otherwise
+func Of(v interface{}) Logger {
+	if p, ok := v.(Provider); ok {
+		if l := p.Logger(); l != nil {
+			return l
+		}
+	}
+	return Nop()
+}
+
+func (nop) Debug(string, ...Field) {}
+func (nop) Info(string, ...Field)  {}
+func (nop) Warn(string, ...Field)  {}
+func (nop) Error(string, ...Field) {}
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type provider struct {
+	l Logger
+}
+
+func (p provider) Logger() Logger { return p.l }
+
+func TestOf(t *testing.T) {
+	l := NewText(nil, LevelError)
+	assert.Equal(t, l, Of(provider{l: l}))
+	assert.Equal(t, Nop(), Of(provider{}))
+	assert.Equal(t, Nop(), Of("not a provider"))
+}
+
+func TestErr(t *testing.T) {
+	err := errors.New("boom")
+	assert.Equal(t, Field{Key: "error", Value: err}, Err(err))
+}
@@ -0,0 +1,181 @@
+package logger
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces every sensitive value
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are compared lowercased without '_' and '-', they cover
+// credentials plus the customer and courier PII sent by the orders API.
+// A key qualified by its parent, such as "phone.number", only matches
+// inside that parent, generic keys like "number" are not redacted alone
+var sensitiveKeys = map[string]bool{
+	"accesstoken":                  true,
+	"refreshtoken":                 true,
+	"token":                        true,
+	"clientsecret":                 true,
+	"secret":                       true,
+	"password":                     true,
+	"authorization":                true,
+	"authorizationcode":            true,
+	"authorizationcodeverifier":    true,
+	"name":                         true,
+	"phone":                        true,
+	"phone.number":                 true,
+	"localizer":                    true,
+	"workername":                   true,
+	"workerphone":                  true,
+	"email":                        true,
+	"document":                     true,
+	"document.number":              true,
+	"documentnumber":               true,
+	"taxpayeridentificationnumber": true,
+	"cpf":                          true,
+	"cnpj":                         true,
+	"cardnumber":                   true,
+	"card.number":                  true,
+	"formattedaddress":             true,
+	"streetname":                   true,
+	"streetnumber":                 true,
+	"postalcode":                   true,
+	"complement":                   true,
+}
+
+var (
+	bearerRe   = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9\-._~+/]+=*`)
+	jsonPairRe = regexp.MustCompile(`"([A-Za-z_\-]+)"(\s*:\s*)("(?:[^"\\]|\\.)*"|-?[0-9][0-9.eE+\-]*)`)
+	jsonKeyRe  = regexp.MustCompile(`"([A-Za-z_\-]+)"\s*:\s*$`)
+	formPairRe = regexp.MustCompile(`\b([A-Za-z_\-]+)=([^&\s"]+)`)
+)
+
+type redactor struct {
+	next Logger
+}
+
+// Redact wraps l so access tokens, credentials and customer PII found
+// in the message or in the fields are replaced by Redacted
+func Redact(l Logger) Logger {
+	if _, ok := l.(redactor); ok {
+		return l
+	}
+	return redactor{next: l}
+}
+
+// IsSensitive tells whether a field or JSON key holds a secret or PII,
+// the key may be qualified by its parents, such as "customer.phone.number"
+func IsSensitive(key string) bool {
+	parts := strings.Split(strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key)), ".")
+	last := parts[len(parts)-1]
+	if sensitiveKeys[last] {
+		return true
+	}
+	return len(parts) > 1 && sensitiveKeys[parts[len(parts)-2]+"."+last]
+}
+
+// RedactString removes bearer tokens plus the sensitive values of
+// JSON and form encoded pairs from s
+func RedactString(s string) string {
+	s = bearerRe.ReplaceAllString(s, "${1}"+Redacted)
+	var out strings.Builder
+	last := 0
+	for _, m := range jsonPairRe.FindAllStringSubmatchIndex(s, -1) {
+		key := s[m[2]:m[3]]
+		if parent := parentKey(s[:m[0]]); parent != "" {
+			key = parent + "." + key
+		}
+		if !IsSensitive(key) {
+			continue
+		}
+		out.WriteString(s[last:m[4]])
+		out.WriteString(s[m[4]:m[5]] + `"` + Redacted + `"`)
+		last = m[1]
+	}
+	s = out.String() + s[last:]
+	return formPairRe.ReplaceAllStringFunc(s, func(pair string) string {
+		m := formPairRe.FindStringSubmatch(pair)
+		if !IsSensitive(m[1]) {
+			return pair
+		}
+		return m[1] + "=" + Redacted
+	})
+}
+
+// parentKey returns the key of the JSON object the text before a pair is
+// in, arrays are looked through so the items of "phones" have it as parent
+func parentKey(before string) string {
+	depth := 0
+	for i := len(before) - 1; i >= 0; i-- {
+		switch before[i] {
+		case '}', ']':
+			depth++
+		case '[':
+			if depth > 0 {
+				depth--
+			}
+		case '{':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			if m := jsonKeyRe.FindStringSubmatch(strings.TrimRight(before[:i], " \t\r\n[")); m != nil {
+				return m[1]
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+func (r redactor) Debug(msg string, fields ...Field) {
+	r.next.Debug(RedactString(msg), redactFields(fields)...)
+}
+
+func (r redactor) Info(msg string, fields ...Field) {
+	r.next.Info(RedactString(msg), redactFields(fields)...)
+}
+
+func (r redactor) Warn(msg string, fields ...Field) {
+	r.next.Warn(RedactString(msg), redactFields(fields)...)
+}
+
+func (r redactor) Error(msg string, fields ...Field) {
+	r.next.Error(RedactString(msg), redactFields(fields)...)
+}
+
+func redactFields(fields []Field) []Field {
+	out := make([]Field, len(fields))
+	for i, f := range fields {
+		out[i] = Field{Key: f.Key, Value: redactValue(f.Key, f.Value)}
+	}
+	return out
+}
+
+func redactValue(key string, value interface{}) interface{} {
+	if IsSensitive(key) {
+		return Redacted
+	}
+	switch v := value.(type) {
+	case string:
+		return RedactString(v)
+	case []byte:
+		return RedactString(string(v))
+	case error:
+		return redactedError{msg: RedactString(v.Error()), err: v}
+	case fmt.Stringer:
+		return RedactString(v.String())
+	}
+	return value
+}
+
+// redactedError keeps the original error reachable with errors.Is/As
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e redactedError) Error() string { return e.msg }
+func (e redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the minimum severity written by a text Logger
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "DEBUG",
+	LevelInfo:  "INFO",
+	LevelWarn:  "WARN",
+	LevelError: "ERROR",
+}
+
+type textLogger struct {
+	mu    sync.Mutex
+	w     io.Writer
+	level Level
+}
+
+// NewText returns a Logger writing one line per entry to w, os.Stderr
+// when nil, entries below level are dropped
+//
+//	2021-06-01T12:00:00Z INFO [SDK] V2GetUserCode success status=200
+func NewText(w io.Writer, level Level) Logger {
+	if w == nil {
+		w = os.Stderr
+	}
+	return &textLogger{w: w, level: level}
+}
+
+func (l *textLogger) Debug(msg string, fields ...Field) { l.write(LevelDebug, msg, fields) }
+func (l *textLogger) Info(msg string, fields ...Field)  { l.write(LevelInfo, msg, fields) }
+func (l *textLogger) Warn(msg string, fields ...Field)  { l.write(LevelWarn, msg, fields) }
+func (l *textLogger) Error(msg string, fields ...Field) { l.write(LevelError, msg, fields) }
+
+func (l *textLogger) write(level Level, msg string, fields []Field) {
+	if level < l.level {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(time.Now().UTC().Format(time.RFC3339))
+	b.WriteString(" " + levelNames[level] + " " + msg)
+	for _, f := range fields {
+		b.WriteString(" " + f.Key + "=" + quote(fmt.Sprint(f.Value)))
+	}
+	b.WriteString("\n")
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.w, b.String())
+}
+
+func quote(s string) string {
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
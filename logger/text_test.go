@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewText(t *testing.T) {
+	out := &bytes.Buffer{}
+	l := NewText(out, LevelInfo)
+	l.Debug("[SDK] dropped")
+	l.Info("[SDK] Orders confirmed", F("orderID", "abc"), F("reason", "two words"))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], ` INFO [SDK] Orders confirmed orderID=abc reason="two words"`)
+}
+
+func TestNewText_NilWriter(t *testing.T) {
+	l := NewText(nil, LevelError)
+	assert.NotPanics(t, func() { l.Debug("[SDK] dropped") })
+	assert.Equal(t, os.Stderr, l.(*textLogger).w)
+}
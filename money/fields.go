@@ -0,0 +1,116 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var moneyType = reflect.TypeOf(Money{})
+
+type (
+	// AmountError lists the amounts of a decoded payload that were not
+	// decimals, they are zero in the decoded value
+	AmountError struct {
+		Fields []FieldError
+	}
+
+	// FieldError is a malformed amount, Path is made of the JSON names
+	// leading to it, such as "items[0].price"
+	FieldError struct {
+		Path  string
+		Value string
+		Err   error
+	}
+)
+
+func (e *AmountError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = fmt.Sprintf("%s '%s'", f.Path, f.Value)
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidAmount.Error(), strings.Join(fields, ", "))
+}
+
+// Is makes errors.Is match the error of any field, such as ErrInvalidAmount
+func (e *AmountError) Is(target error) bool {
+	for _, f := range e.Fields {
+		if errors.Is(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an *AmountError listing the malformed amounts
+// reachable from v, nil when there is none
+func Validate(v interface{}) error {
+	var fields []FieldError
+	Walk(v, func(path string, m *Money) {
+		if err := m.Err(); err != nil {
+			fields = append(fields, FieldError{Path: path, Value: m.invalid, Err: err})
+		}
+	})
+	if len(fields) == 0 {
+		return nil
+	}
+	return &AmountError{Fields: fields}
+}
+
+// SetCurrency sets currency on the amounts reachable from v, a pointer,
+// that have none
+func SetCurrency(v interface{}, currency string) {
+	Walk(v, func(path string, m *Money) {
+		if m.currency == "" {
+			m.currency = currency
+		}
+	})
+}
+
+// Walk calls f with every amount reachable from v, a pointer, through
+// exported fields, slices and arrays, along with its JSON path
+func Walk(v interface{}, f func(path string, m *Money)) {
+	walk(reflect.ValueOf(v), "", f)
+}
+
+func walk(v reflect.Value, path string, f func(path string, m *Money)) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			walk(v.Elem(), path, f)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), f)
+		}
+	case reflect.Struct:
+		if v.Type() == moneyType {
+			if v.CanAddr() {
+				f(path, v.Addr().Interface().(*Money))
+			}
+			return
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			switch {
+			case name == "-":
+				continue
+			case name == "" && field.Anonymous:
+				walk(v.Field(i), path, f)
+				continue
+			case name == "":
+				name = field.Name
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			walk(v.Field(i), name, f)
+		}
+	}
+}
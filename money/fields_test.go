@@ -0,0 +1,54 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	Price Money `json:"price"`
+	Skip  Money `json:"-"`
+}
+
+type testOrder struct {
+	Items   []testItem `json:"items"`
+	Total   *Money     `json:"total"`
+	Payment struct {
+		Value Money
+	} `json:"payment"`
+	fee Money
+}
+
+func TestValidate(t *testing.T) {
+	var o testOrder
+	in := `{"items":[{"price":"1.50"},{"price":"Preço"}],"total":"Total do pedido","payment":{"Value":1.5}}`
+	require.Nil(t, json.Unmarshal([]byte(in), &o))
+	err := Validate(&o)
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidAmount))
+	assert.False(t, errors.Is(err, ErrOverflow))
+	var amountErr *AmountError
+	require.True(t, errors.As(err, &amountErr))
+	assert.Equal(t, []FieldError{
+		{Path: "items[1].price", Value: "Preço", Err: amountErr.Fields[0].Err},
+		{Path: "total", Value: "Total do pedido", Err: amountErr.Fields[1].Err},
+	}, amountErr.Fields)
+	assert.Equal(t, "invalid money amount: items[1].price 'Preço', total 'Total do pedido'", err.Error())
+	assert.True(t, o.Items[1].Price.IsZero())
+
+	o.Items, o.Total = o.Items[:1], nil
+	assert.Nil(t, Validate(&o))
+}
+
+func TestSetCurrency(t *testing.T) {
+	o := testOrder{Items: []testItem{{Price: FromCents(150).WithCurrency("USD")}, {Price: FromCents(200)}}}
+	SetCurrency(&o, "EUR")
+	assert.Equal(t, "USD", o.Items[0].Price.Currency())
+	assert.Equal(t, "EUR", o.Items[1].Price.Currency())
+	assert.Equal(t, "EUR", o.Payment.Value.Currency())
+	assert.Equal(t, DefaultCurrency, o.fee.Currency())
+}
@@ -0,0 +1,280 @@
+// Package money holds the exact decimal amounts of order and catalog
+// prices, iFood sends them as JSON numbers or strings such as 12.90
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Precision is how many decimal places an amount keeps
+	Precision = 4
+	// DefaultCurrency is the currency of amounts that do not set one
+	DefaultCurrency = "BRL"
+
+	scale = 10000
+)
+
+var (
+	// ErrInvalidAmount the value is not a decimal amount
+	ErrInvalidAmount = errors.New("invalid money amount")
+	// ErrPrecision the value has more decimal places than Precision
+	ErrPrecision = errors.New("money amount is too precise")
+	// ErrOverflow the amount does not fit
+	ErrOverflow = errors.New("money amount overflows")
+	// ErrCurrencyMismatch the amounts are in different currencies
+	ErrCurrencyMismatch = errors.New("money currencies differ")
+)
+
+// Money is an exact decimal amount in a currency, the zero value is 0 BRL.
+// It decodes from JSON numbers and strings, and encodes back the same way,
+// a value that is not a decimal decodes as zero, see Err and Validate.
+// Compare amounts with Equal or Cmp
+type Money struct {
+	units    int64 // 1/10000 of the currency unit
+	currency string
+	quoted   bool
+	// invalid is the JSON value that could not be decoded
+	invalid string
+}
+
+// FromCents returns the amount of cents
+func FromCents(cents int64) Money {
+	return Money{units: cents * (scale / 100)}
+}
+
+// FromFloat returns f rounded to Precision decimal places
+func FromFloat(f float64) Money {
+	return Money{units: int64(math.Round(f * scale))}
+}
+
+// Parse reads a decimal such as "12.90", "-3" or "1.5e1"
+func Parse(s string) (m Money, err error) {
+	trimmed := strings.TrimSpace(s)
+	// big.Rat also reads fractions and prefixed bases
+	if trimmed == "" || strings.Trim(trimmed, "0123456789+-.eE") != "" {
+		return m, fmt.Errorf("%w: '%s'", ErrInvalidAmount, s)
+	}
+	r, ok := new(big.Rat).SetString(trimmed)
+	if !ok {
+		return m, fmt.Errorf("%w: '%s'", ErrInvalidAmount, s)
+	}
+	r.Mul(r, big.NewRat(scale, 1))
+	if !r.IsInt() {
+		return m, fmt.Errorf("%w: '%s'", ErrPrecision, s)
+	}
+	if !r.Num().IsInt64() {
+		return m, fmt.Errorf("%w: '%s'", ErrOverflow, s)
+	}
+	return Money{units: r.Num().Int64()}, nil
+}
+
+// MustParse works like Parse, panicking on invalid values
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Sum adds the values, zero when there is none
+func Sum(values ...Money) (total Money, err error) {
+	for _, v := range values {
+		if total, err = total.Add(v); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// WithCurrency returns the amount in the given currency
+func (m Money) WithCurrency(currency string) Money {
+	m.currency = currency
+	return m
+}
+
+// Err returns the error of a malformed JSON value the amount was
+// decoded from, such as ErrInvalidAmount, nil for valid amounts
+func (m Money) Err() error {
+	if m.invalid == "" {
+		return nil
+	}
+	_, err := Parse(m.invalid)
+	return err
+}
+
+// Currency returns the currency, DefaultCurrency when unset
+func (m Money) Currency() string {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+// Add returns m+o
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.sameCurrency(o); err != nil {
+		return Money{}, err
+	}
+	sum := m.units + o.units
+	if (o.units > 0 && sum < m.units) || (o.units < 0 && sum > m.units) {
+		return Money{}, ErrOverflow
+	}
+	m.units = sum
+	return m.inherit(o), nil
+}
+
+// Sub returns m-o
+func (m Money) Sub(o Money) (Money, error) {
+	if o.units == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(o.Neg())
+}
+
+// Mul returns m times n, such as the unit price times the quantity
+func (m Money) Mul(n int64) (Money, error) {
+	if m.units == 0 || n == 0 {
+		m.units = 0
+		return m, nil
+	}
+	product := m.units * n
+	if product/n != m.units || (m.units == -1 && n == math.MinInt64) || (n == -1 && m.units == math.MinInt64) {
+		return Money{}, ErrOverflow
+	}
+	m.units = product
+	return m, nil
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	m.units = -m.units
+	return m
+}
+
+// Cmp returns -1, 0 or +1 as m is less than, equal to or greater than o,
+// the currencies are not compared
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.units < o.units:
+		return -1
+	case m.units > o.units:
+		return 1
+	}
+	return 0
+}
+
+// Equal tells whether the amounts and currencies are the same
+func (m Money) Equal(o Money) bool {
+	return m.units == o.units && m.Currency() == o.Currency()
+}
+
+// IsZero tells whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// IsNegative tells whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+// Cents returns the amount in cents, rounded half away from zero
+func (m Money) Cents() int64 {
+	const per = scale / 100
+	cents, rest := m.units/per, m.units%per
+	switch {
+	case rest >= per/2:
+		cents++
+	case rest <= -per/2:
+		cents--
+	}
+	return cents
+}
+
+// Float64 returns the nearest float, for display only
+func (m Money) Float64() float64 {
+	return float64(m.units) / scale
+}
+
+// String formats the amount with at least two decimal places, such as "12.90"
+func (m Money) String() string {
+	u := new(big.Int).SetInt64(m.units)
+	sign := ""
+	if u.Sign() < 0 {
+		sign = "-"
+		u.Neg(u)
+	}
+	digits := u.String()
+	if len(digits) <= Precision {
+		digits = strings.Repeat("0", Precision+1-len(digits)) + digits
+	}
+	whole, frac := digits[:len(digits)-Precision], strings.TrimRight(digits[len(digits)-Precision:], "0")
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	return sign + whole + "." + frac
+}
+
+// MarshalJSON encodes the amount as a number, or a string when it was
+// decoded from one, a malformed value is encoded back as it was received
+func (m Money) MarshalJSON() ([]byte, error) {
+	if m.invalid != "" {
+		return []byte(strconv.Quote(m.invalid)), nil
+	}
+	if m.quoted {
+		return []byte(strconv.Quote(m.String())), nil
+	}
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a number or a string, null and "" are zero, a
+// value that is not a decimal is zero and kept for Err, so one bad price
+// does not fail the whole payload
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	quoted := len(data) > 0 && data[0] == '"'
+	s := string(data)
+	if quoted {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if strings.TrimSpace(s) == "" {
+			*m = Money{currency: m.currency, quoted: true}
+			return nil
+		}
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		*m = Money{currency: m.currency, quoted: quoted, invalid: s}
+		return nil
+	}
+	*m = Money{units: parsed.units, currency: m.currency, quoted: quoted}
+	return nil
+}
+
+func (m Money) sameCurrency(o Money) error {
+	if m.Currency() == o.Currency() {
+		return nil
+	}
+	return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), o.Currency())
+}
+
+// inherit takes the currency of o when m has none
+func (m Money) inherit(o Money) Money {
+	if m.currency == "" {
+		m.currency = o.currency
+	}
+	return m
+}
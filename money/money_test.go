@@ -0,0 +1,113 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for in, want := range map[string]string{
+		"12.90":   "12.90",
+		"12.9":    "12.90",
+		"12":      "12.00",
+		"-3.5":    "-3.50",
+		"0.125":   "0.125",
+		"0.0001":  "0.0001",
+		"-0.05":   "-0.05",
+		"1.5e1":   "15.00",
+		" 7.10 ":  "7.10",
+		"1000000": "1000000.00",
+	} {
+		m, err := Parse(in)
+		require.Nil(t, err, in)
+		assert.Equal(t, want, m.String(), in)
+	}
+	for in, want := range map[string]error{
+		"":          ErrInvalidAmount,
+		"abc":       ErrInvalidAmount,
+		"1/4":       ErrInvalidAmount,
+		"0x10":      ErrInvalidAmount,
+		"Preço":     ErrInvalidAmount,
+		"0.00001":   ErrPrecision,
+		"1e20":      ErrOverflow,
+		"12.90.1":   ErrInvalidAmount,
+		"--1":       ErrInvalidAmount,
+		"1.123456":  ErrPrecision,
+		"999999e99": ErrOverflow,
+	} {
+		_, err := Parse(in)
+		assert.True(t, errors.Is(err, want), "%s: %v", in, err)
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	price := MustParse("12.90")
+	total, err := price.Mul(3)
+	require.Nil(t, err)
+	assert.Equal(t, "38.70", total.String())
+	total, err = total.Sub(MustParse("0.70"))
+	require.Nil(t, err)
+	assert.Equal(t, "38.00", total.String())
+	sum, err := Sum(FromCents(1090), FromCents(5), MustParse("0.001"))
+	require.Nil(t, err)
+	assert.Equal(t, "10.951", sum.String())
+	assert.Equal(t, int64(1095), sum.Cents())
+	assert.Equal(t, int64(-1095), sum.Neg().Cents())
+	assert.Equal(t, int64(1096), MustParse("10.955").Cents())
+	assert.InDelta(t, 10.951, sum.Float64(), 1e-9)
+
+	assert.Equal(t, -1, price.Cmp(total))
+	assert.Equal(t, 0, price.Cmp(FromFloat(12.9)))
+	assert.True(t, price.Equal(FromCents(1290)))
+	assert.True(t, price.Equal(FromCents(1290).WithCurrency(DefaultCurrency)))
+	assert.False(t, price.Equal(FromCents(1290).WithCurrency("USD")))
+	assert.True(t, Money{}.IsZero())
+	assert.True(t, price.Neg().IsNegative())
+
+	_, err = price.Add(FromCents(1).WithCurrency("USD"))
+	assert.True(t, errors.Is(err, ErrCurrencyMismatch))
+	usd, err := FromCents(1).WithCurrency("USD").Add(FromCents(2).WithCurrency("USD"))
+	require.Nil(t, err)
+	assert.Equal(t, "USD", usd.Currency())
+
+	max := Money{units: math.MaxInt64}
+	_, err = max.Add(FromCents(1))
+	assert.True(t, errors.Is(err, ErrOverflow))
+	_, err = max.Mul(2)
+	assert.True(t, errors.Is(err, ErrOverflow))
+	_, err = FromCents(1).Sub(Money{units: math.MinInt64})
+	assert.True(t, errors.Is(err, ErrOverflow))
+}
+
+func TestMoney_JSON(t *testing.T) {
+	var v struct {
+		Number  Money  `json:"number"`
+		Text    Money  `json:"text"`
+		Null    Money  `json:"null"`
+		Empty   Money  `json:"empty"`
+		Pointer *Money `json:"pointer,omitempty"`
+	}
+	in := `{"number":12.9,"text":"12.90","null":null,"empty":""}`
+	require.Nil(t, json.Unmarshal([]byte(in), &v))
+	assert.True(t, v.Number.Equal(MustParse("12.90")))
+	assert.True(t, v.Text.Equal(v.Number))
+	assert.True(t, v.Null.IsZero())
+	assert.True(t, v.Empty.IsZero())
+	out, err := json.Marshal(v)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"number":12.90,"text":"12.90","null":0.00,"empty":"0.00"}`, string(out))
+
+	require.Nil(t, json.Unmarshal([]byte(`{"number":"twelve","text":true}`), &v))
+	assert.True(t, v.Number.IsZero())
+	assert.True(t, errors.Is(v.Number.Err(), ErrInvalidAmount))
+	assert.True(t, errors.Is(v.Text.Err(), ErrInvalidAmount))
+	assert.Nil(t, v.Empty.Err())
+	out, err = json.Marshal(v)
+	require.Nil(t, err)
+	assert.JSONEq(t, `{"number":"twelve","text":"true","null":0.00,"empty":"0.00"}`, string(out))
+}
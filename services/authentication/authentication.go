@@ -2,17 +2,19 @@ package authentication
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
-	"github.com/kpango/glg"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 )
 
 const (
@@ -20,10 +22,20 @@ const (
 	authEndpoint     = "/oauth/token"
 	userCodeEndpoint = "/oauth/userCode"
 	valueGrantType   = "password"
+	// v1Lifetime and v2Lifetime are used when the API omits expiresIn
+	v1Lifetime = time.Hour
+	v2Lifetime = time.Hour * 6
+	// maxRenewMargin caps how long before expiry a token is renewed
+	maxRenewMargin = time.Minute * 5
+	// renewTimeout bounds a renewal, it does not depend on the callers
+	renewTimeout = time.Second * 30
+	// renewBackoff delays the next renewal after a failed one, it doubles
+	// on each failure up to maxRenewMargin
+	renewBackoff = time.Second * 5
 )
 
-// ErrUnauthorized API no auth error
-var ErrUnauthorized = errors.New("Unauthorized")
+// ErrUnauthorized API no auth error, matches an *apierror.APIError with errors.Is
+var ErrUnauthorized = apierror.ErrUnauthorized
 var ErrGrantType = errors.New("Grant type is invalid, should be 'client_credentials', 'authorization_code' or 'refresh_token'")
 var ErrNoRefreshToken = errors.New("Grant type 'refresh_token', should have a refresh token provided")
 var ErrNoAuthCodeOrVerifier = errors.New("Grant type 'authorization_code', should have both 'authorizationCode' and 'authorizationCodeVerifier' provided")
@@ -32,10 +44,18 @@ type (
 	// Service describes the auth service abstraction
 	Service interface {
 		V2GetUserCode() (*UserCode, error)
+		V2GetUserCodeCtx(ctx context.Context) (*UserCode, error)
 		Authenticate(username, password string) (*Credentials, error)
-		V2Authenticate(authType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error)
+		AuthenticateCtx(ctx context.Context, username, password string) (*Credentials, error)
+		V2Authenticate(authType GrantType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error)
+		V2AuthenticateCtx(ctx context.Context, authType GrantType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error)
 		Validate() error
+		ValidateCtx(ctx context.Context) error
 		GetToken() string
+		// Claims decodes the current token offline, see ParseClaims
+		Claims() (*Claims, error)
+		HasScope(scope string) bool
+		Merchants() []string
 	}
 
 	// Credentials describes the API credential type
@@ -51,14 +71,7 @@ type (
 		AccessToken  string `json:"accessToken"`
 		RefreshToken string `json:"refreshToken"`
 		Type         string `json:"type"`
-		ExpiresIn    int    `json:"expires_in"`
-	}
-
-	authError struct {
-		Error struct {
-			Code    string `json:"code"`
-			Message string `json:"error"`
-		} `json:"error"`
+		ExpiresIn    int    `json:"expiresIn"`
 	}
 
 	UserCode struct {
@@ -69,90 +82,145 @@ type (
 		ExpiresIn                 int    `json:"expiresIn"`
 	}
 
+	// authService is safe for concurrent use, mu guards the
+	// credentials and the in flight renewal
 	authService struct {
 		adapter                adapters.Http
 		clientId, clientSecret string
+		mu                     sync.RWMutex
 		username, password     string
 		currentExpiration      time.Time
+		renewAt                time.Time
 		Token                  string
 		refreshToken           string
+		grant                  GrantType
+		scope                  string
+		renewal                *renewal
+		renewFailures          int
 		v2                     bool
+		log                    logger.Logger
+		store                  TokenStore
+		storeKey               string
+		loaded                 bool
+	}
+
+	// Option customizes the authentication service
+	Option func(*authService)
+
+	// renewal is a token refresh shared by every concurrent caller
+	renewal struct {
+		done chan struct{}
+		err  error
 	}
 )
 
 // New returns an auth service implementation
-func New(adapter adapters.Http, clientId, clientSecret string, v2 bool) Service {
-	return &authService{adapter: adapter, clientId: clientId, clientSecret: clientSecret, v2: v2}
+func New(adapter adapters.Http, clientId, clientSecret string, v2 bool, opts ...Option) Service {
+	a := &authService{adapter: adapter, clientId: clientId, clientSecret: clientSecret, v2: v2, log: logger.Of(adapter)}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// WithTokenStore persists the V2 credentials in store under key, the
+// client id when key is empty, Validate resumes from the stored
+// token after a restart
+func WithTokenStore(store TokenStore, key string) Option {
+	return func(a *authService) {
+		a.store = store
+		a.storeKey = key
+		if key == "" {
+			a.storeKey = a.clientId
+		}
+	}
 }
 
 func (a *authService) V2GetUserCode() (uc *UserCode, err error) {
+	return a.V2GetUserCodeCtx(context.Background())
+}
+
+// V2GetUserCodeCtx works like V2GetUserCode, honoring ctx cancellation and deadlines
+func (a *authService) V2GetUserCodeCtx(ctx context.Context) (uc *UserCode, err error) {
 	params := url.Values{}
 	params.Add("clientId", a.clientId)
 	body := strings.NewReader(params.Encode())
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
-	resp, status, err := a.adapter.DoRequest(http.MethodPost, authRoot+userCodeEndpoint, body, headers)
+	resp, status, err := a.adapter.DoRequestCtx(ctx, http.MethodPost, authRoot+userCodeEndpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] (V2GetUserCode::DoRequest) error: ", err.Error())
+		a.log.Error("[SDK] (V2GetUserCode::DoRequest) error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Warn("[SDK] (V2GetUserCode::status.check): status code ", status)
-		err = ErrUnauthorized
+		a.log.Warn("[SDK] (V2GetUserCode::status.check):", logger.F("status", status))
+		err = apierror.New("User code could not be requested", status, authRoot+userCodeEndpoint, resp).
+			WithKind(ErrUnauthorized)
 		return
 	}
 	if err = json.Unmarshal(resp, &uc); err != nil {
-		glg.Error("[SDK] (V2GetUserCode::Unmarshal) error: ", err)
+		a.log.Error("[SDK] (V2GetUserCode::Unmarshal) error", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] V2GetUserCode success")
+	a.log.Info("[SDK] V2GetUserCode success")
 	return
 }
 
-// V2Authenticate queries the iFood API for a credential
-func (a *authService) V2Authenticate(authType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error) {
+// V2Authenticate queries the iFood API for a credential, authCode and
+// authCodeVerifier are only sent with GrantAuthorizationCode and
+// refreshToken only with GrantRefreshToken
+func (a *authService) V2Authenticate(authType GrantType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error) {
+	return a.V2AuthenticateCtx(context.Background(), authType, authCode, authCodeVerifier, refreshToken)
+}
+
+// V2AuthenticateCtx works like V2Authenticate, honoring ctx cancellation and deadlines
+func (a *authService) V2AuthenticateCtx(ctx context.Context, authType GrantType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error) {
 	if err = verifyV2Inputs(authType, authCode, authCodeVerifier, refreshToken); err != nil {
-		glg.Error("[SDK] (V2Authenticate::verifyV2Inputs) error: ", err.Error())
+		a.log.Error("[SDK] (V2Authenticate::verifyV2Inputs) error", logger.Err(err))
 		return
 	}
-	data := url.Values{}
-	data.Set("client_id", a.clientId)
-	data.Set("client_secret", a.clientSecret)
-	data.Set("grant_type", valueGrantType)
-	data.Set("authorizationCode", authCode)
-	data.Set("authorizationCodeVerifier", authCodeVerifier)
-	data.Set("refreshToken", refreshToken)
+	data := a.tokenForm(authType, authCode, authCodeVerifier, refreshToken)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 	body := strings.NewReader(data.Encode())
-	resp, status, err := a.adapter.DoRequest(http.MethodPost, authRoot+authEndpoint, body, headers)
+	resp, status, err := a.adapter.DoRequestCtx(ctx, http.MethodPost, authRoot+authEndpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] (V2Authenticate::DoRequest) error: ", err.Error())
+		a.log.Error("[SDK] (V2Authenticate::DoRequest) error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Warn("[SDK] V2Authenticate: status code ", status)
-		authErr := authError{}
-		json.Unmarshal(resp, &authErr)
-		warn := fmt.Sprintf("[SDK] (V2GetUserCode::status.code): code '%s' message '%s'",
-			authErr.Error.Code, authErr.Error.Message)
-		glg.Warn(warn)
-		err = ErrUnauthorized
+		err = apierror.New("Could not authenticate", status, authRoot+authEndpoint, resp).
+			WithKind(ErrUnauthorized)
+		a.log.Warn("[SDK] (V2Authenticate::status.code)", logger.Err(err))
 		return
 	}
 	if err = json.Unmarshal(resp, &c); err != nil {
-		glg.Error("[SDK] (V2Authenticate::Unmarshal) error: ", err)
+		a.log.Error("[SDK] (V2Authenticate::Unmarshal) error", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] (V2Authenticate) success")
-	a.currentExpiration = time.Now().Add(time.Hour * 6)
-	a.Token = c.AccessToken
-	a.refreshToken = c.RefreshToken
+	a.log.Info("[SDK] (V2Authenticate) success")
+	a.mu.Lock()
+	a.setToken(c.AccessToken, c.ExpiresIn, v2Lifetime)
+	if c.RefreshToken != "" {
+		a.refreshToken = c.RefreshToken
+	}
+	if authType != GrantRefreshToken {
+		a.grant = authType
+	}
+	token := Token{AccessToken: a.Token, RefreshToken: a.refreshToken, ExpiresAt: a.currentExpiration, Grant: a.grant}
+	a.loaded = true
+	a.mu.Unlock()
+	a.saveToken(ctx, token)
 	return
 }
 
 // Authenticate queries the iFood API for a credential
 func (a *authService) Authenticate(username, password string) (c *Credentials, err error) {
+	return a.AuthenticateCtx(context.Background(), username, password)
+}
+
+// AuthenticateCtx works like Authenticate, honoring ctx cancellation and deadlines
+func (a *authService) AuthenticateCtx(ctx context.Context, username, password string) (c *Credentials, err error) {
 	payload := &bytes.Buffer{}
 	writer := multipart.NewWriter(payload)
 	writer.WriteField("client_id", a.clientId)
@@ -161,65 +229,276 @@ func (a *authService) Authenticate(username, password string) (c *Credentials, e
 	writer.WriteField("username", username)
 	writer.WriteField("password", password)
 	if err = writer.Close(); err != nil {
-		glg.Error("[SDK] (Auth::writer.Close) error: ", err.Error())
+		a.log.Error("[SDK] (Auth::writer.Close) error", logger.Err(err))
 		return
 	}
 	reader := bytes.NewReader(payload.Bytes())
 	headers := make(map[string]string)
 	headers["Content-Type"] = writer.FormDataContentType()
 	headers["Accept"] = "*/*"
-	resp, status, err := a.adapter.DoRequest(http.MethodPost, authEndpoint, reader, headers)
+	resp, status, err := a.adapter.DoRequestCtx(ctx, http.MethodPost, authEndpoint, reader, headers)
 	if err != nil {
-		glg.Error("[SDK] (Auth::DoRequest) error: ", err.Error())
+		a.log.Error("[SDK] (Auth::DoRequest) error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Warn("[SDK] (Auth::status.code): status code ", status)
-		err = ErrUnauthorized
+		a.log.Warn("[SDK] (Auth::status.code):", logger.F("status", status))
+		err = apierror.New("Could not authenticate", status, authEndpoint, resp).
+			WithKind(ErrUnauthorized)
 		return
 	}
 	if err = json.Unmarshal(resp, &c); err != nil {
-		glg.Error("[SDK] (Auth::Unmarshal) error: ", err)
+		a.log.Error("[SDK] (Auth::Unmarshal) error", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] (Authenticate) success")
-	a.currentExpiration = time.Now().Add(time.Hour)
+	a.log.Info("[SDK] (Authenticate) success")
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.setToken(c.AccessToken, c.ExpiresIn, v1Lifetime)
+	a.scope = c.Scope
 	a.username = username
 	a.password = password
-	a.Token = c.AccessToken
 	return
 }
 
 // Validate validates or renews a token auth
 func (a *authService) Validate() (err error) {
-	if !time.Now().After(a.currentExpiration) {
-		glg.Debug("[SDK] (auth::Validate) not time")
+	return a.ValidateCtx(context.Background())
+}
+
+// ValidateCtx works like Validate, honoring ctx cancellation and deadlines
+//
+// The token is renewed shortly before it expires, concurrent callers
+// share a single renewal and only the ones holding an expired
+// token wait for it
+func (a *authService) ValidateCtx(ctx context.Context) (err error) {
+	a.loadToken(ctx)
+	a.mu.Lock()
+	now := time.Now()
+	if now.Before(a.renewAt) {
+		a.mu.Unlock()
+		a.log.Debug("[SDK] (auth::Validate) not time")
 		return
 	}
-	glg.Info("[SDK] (auth::Validate) Renewing Auth")
-	if a.v2 {
-		_, err = a.V2Authenticate("refresh_token", "", "", a.refreshToken)
+	expired := !now.Before(a.currentExpiration)
+	r := a.renewal
+	if r == nil {
+		r = &renewal{done: make(chan struct{})}
+		a.renewal = r
+		username, password, refreshToken, grant := a.username, a.password, a.refreshToken, a.grant
+		go a.renew(r, username, password, refreshToken, grant)
+	}
+	a.mu.Unlock()
+	if !expired {
 		return
 	}
-	_, err = a.Authenticate(a.username, a.password)
-	return
+	return a.wait(ctx, r)
+}
+
+// renew refreshes the token on its own context, a caller giving up
+// does not fail the renewal the others wait for. Application credentials
+// without a refresh token are requested again with the client id and
+// secret, merchant credentials need their refresh token
+func (a *authService) renew(r *renewal, username, password, refreshToken string, grant GrantType) {
+	ctx, cancel := context.WithTimeout(context.Background(), renewTimeout)
+	defer cancel()
+	a.log.Info("[SDK] (auth::Validate) Renewing Auth")
+	switch {
+	case !a.v2:
+		_, r.err = a.AuthenticateCtx(ctx, username, password)
+	case refreshToken == "" && grant != GrantAuthorizationCode:
+		_, r.err = a.V2AuthenticateCtx(ctx, GrantClientCredentials, "", "", "")
+	default:
+		_, r.err = a.V2AuthenticateCtx(ctx, GrantRefreshToken, "", "", refreshToken)
+	}
+	revoked := a.v2 && rejected(r.err)
+	if revoked {
+		a.deleteToken(ctx)
+	}
+	a.mu.Lock()
+	a.renewal = nil
+	if revoked && a.refreshToken == refreshToken {
+		a.refreshToken = ""
+	}
+	if r.err == nil {
+		a.renewFailures = 0
+	} else {
+		a.backoff()
+	}
+	a.mu.Unlock()
+	if r.err != nil {
+		a.log.Warn("[SDK] (auth::Validate) renewal failed", logger.Err(r.err))
+	}
+	close(r.done)
+}
+
+// backoff delays the next renewal after a failure, never past the
+// expiry of the current token. Must be called with a.mu held
+func (a *authService) backoff() {
+	delay := renewBackoff << uint(a.renewFailures)
+	if delay < maxRenewMargin {
+		a.renewFailures++
+	} else {
+		delay = maxRenewMargin
+	}
+	next := time.Now().Add(delay)
+	if next.After(a.currentExpiration) {
+		next = a.currentExpiration
+	}
+	if next.After(a.renewAt) {
+		a.renewAt = next
+	}
+}
+
+// wait returns once the renewal is done or ctx is
+func (a *authService) wait(ctx context.Context, r *renewal) error {
+	select {
+	case <-r.done:
+		return r.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
 // GetToken returns the last valid token
 func (a *authService) GetToken() (token string) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
 	return a.Token
 }
 
-func verifyV2Inputs(authType, authCode, authCodeVerifier, refreshToken string) (err error) {
-	if (authType != "client_credentials") && (authType != "authorization_code") && (authType != "refresh_token") {
+// Claims decodes the claims of the current token
+func (a *authService) Claims() (*Claims, error) {
+	return ParseClaims(a.GetToken())
+}
+
+// HasScope tells whether the current token was granted scope, the
+// V1 credentials scope is used when the token is not a JWT
+func (a *authService) HasScope(scope string) bool {
+	if c, err := a.Claims(); err == nil {
+		return c.HasScope(scope)
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return Credentials{Scope: a.scope}.hasScope(scope)
+}
+
+// Merchants lists the merchants covered by the current token
+func (a *authService) Merchants() []string {
+	c, err := a.Claims()
+	if err != nil {
+		return nil
+	}
+	return c.Merchants()
+}
+
+// Invalidate drops token when it is still the current one, the next
+// Validate renews it and waits for the renewal
+func (a *authService) Invalidate(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.Token != token {
+		return
+	}
+	a.renewAt = time.Time{}
+	a.currentExpiration = time.Time{}
+}
+
+// loadToken resumes from the stored token once, the store is read
+// without holding a.mu
+func (a *authService) loadToken(ctx context.Context) {
+	a.mu.RLock()
+	skip := a.store == nil || a.loaded || !a.v2
+	a.mu.RUnlock()
+	if skip {
+		return
+	}
+	t, err := a.store.Load(ctx, a.storeKey)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loaded {
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, ErrTokenNotFound) {
+			a.log.Warn("[SDK] (auth::TokenStore) load", logger.Err(err))
+		}
+		if ctx.Err() == nil {
+			a.loaded = true
+		}
+		return
+	}
+	a.loaded = true
+	a.Token, a.refreshToken = t.AccessToken, t.RefreshToken
+	if t.Grant != "" {
+		a.grant = t.Grant
+	}
+	a.currentExpiration = t.ExpiresAt
+	a.renewAt = t.ExpiresAt.Add(-renewMargin(time.Until(t.ExpiresAt)))
+	a.log.Info("[SDK] (auth::TokenStore) token loaded")
+}
+
+func (a *authService) saveToken(ctx context.Context, token Token) {
+	if a.store == nil {
+		return
+	}
+	if err := a.store.Save(ctx, a.storeKey, token); err != nil {
+		a.log.Warn("[SDK] (auth::TokenStore) save", logger.Err(err))
+	}
+}
+
+func (a *authService) deleteToken(ctx context.Context) {
+	if a.store == nil {
+		return
+	}
+	if err := a.store.Delete(ctx, a.storeKey); err != nil {
+		a.log.Warn("[SDK] (auth::TokenStore) delete", logger.Err(err))
+	}
+}
+
+// rejected tells whether the API refused the refresh token itself
+func rejected(err error) bool {
+	var apiErr *apierror.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Status == http.StatusBadRequest || apiErr.Status == http.StatusUnauthorized
+}
+
+// setToken stores a new token, a.mu must be held, the token is renewed
+// a tenth of its lifetime before expiring, at most maxRenewMargin before
+func (a *authService) setToken(token string, expiresIn int, fallback time.Duration) {
+	lifetime := time.Duration(expiresIn) * time.Second
+	if lifetime <= 0 {
+		lifetime = fallback
+	}
+	a.Token = token
+	a.currentExpiration = time.Now().Add(lifetime)
+	a.renewAt = a.currentExpiration.Add(-renewMargin(lifetime))
+}
+
+// renewMargin is a tenth of the lifetime, at most maxRenewMargin
+func renewMargin(lifetime time.Duration) time.Duration {
+	if lifetime <= 0 {
+		return 0
+	}
+	margin := lifetime / 10
+	if margin > maxRenewMargin {
+		margin = maxRenewMargin
+	}
+	return margin
+}
+
+func verifyV2Inputs(authType GrantType, authCode, authCodeVerifier, refreshToken string) (err error) {
+	if !authType.Valid() {
 		return ErrGrantType
 	}
 	switch authType {
-	case "authorization_code":
+	case GrantAuthorizationCode:
 		if authCode == "" || authCodeVerifier == "" {
 			return ErrNoAuthCodeOrVerifier
 		}
-	case "refresh_token":
+	case GrantRefreshToken:
 		if refreshToken == "" {
 			return ErrNoRefreshToken
 		}
@@ -1,12 +1,18 @@
 package authentication
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
 	"github.com/arxdsilva/golang-ifood-sdk/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,7 +58,7 @@ func TestAuth_NotOK(t *testing.T) {
 	c, err := as.Authenticate("user", "pass")
 	assert.Nil(t, c)
 	assert.NotNil(t, err)
-	assert.Equal(t, err, ErrUnauthorized)
+	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
 func TestAuth_BadResp(t *testing.T) {
@@ -74,7 +80,7 @@ func TestAuth_BadResp(t *testing.T) {
 }
 
 func Test_verifyV2Inputs_ErrGrantType(t *testing.T) {
-	authType := ""
+	authType := GrantType("")
 	authCode := ""
 	authCodeVerifier := ""
 	refreshToken := ""
@@ -84,7 +90,7 @@ func Test_verifyV2Inputs_ErrGrantType(t *testing.T) {
 }
 
 func Test_verifyV2Inputs_ErrNoAuthCodeOrVerifier(t *testing.T) {
-	authType := "authorization_code"
+	authType := GrantAuthorizationCode
 	authCode := ""
 	authCodeVerifier := ""
 	refreshToken := ""
@@ -94,7 +100,7 @@ func Test_verifyV2Inputs_ErrNoAuthCodeOrVerifier(t *testing.T) {
 }
 
 func Test_verifyV2Inputs_ErrNoRefreshToken(t *testing.T) {
-	authType := "refresh_token"
+	authType := GrantRefreshToken
 	authCode := "testCode"
 	authCodeVerifier := "testToken"
 	refreshToken := ""
@@ -104,7 +110,7 @@ func Test_verifyV2Inputs_ErrNoRefreshToken(t *testing.T) {
 }
 
 func Test_verifyV2Inputs_OK_authorization_code(t *testing.T) {
-	authType := "authorization_code"
+	authType := GrantAuthorizationCode
 	authCode := "testCode"
 	authCodeVerifier := "testToken"
 	refreshToken := ""
@@ -113,7 +119,7 @@ func Test_verifyV2Inputs_OK_authorization_code(t *testing.T) {
 }
 
 func Test_verifyV2Inputs_OK_refresh_token(t *testing.T) {
-	authType := "refresh_token"
+	authType := GrantRefreshToken
 	authCode := ""
 	authCodeVerifier := ""
 	refreshToken := "TOKEN"
@@ -156,7 +162,7 @@ func TestV2Auth_RespNotOK(t *testing.T) {
 	c, err := as.V2Authenticate("authorization_code", "testCode", "verifier", "refresh")
 	assert.Nil(t, c)
 	assert.NotNil(t, err)
-	assert.Equal(t, ErrUnauthorized, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
 func Test_V2GetUserCode_OK(t *testing.T) {
@@ -205,5 +211,245 @@ func Test_V2GetUserCode_ErrUnauthorized(t *testing.T) {
 	uc, err := as.V2GetUserCode()
 	assert.Nil(t, uc)
 	assert.NotNil(t, err)
-	assert.Equal(t, ErrUnauthorized, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func Test_ValidateCtx_SingleRenewal(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, "refresh", r.PostForm.Get("refreshToken"))
+			time.Sleep(50 * time.Millisecond)
+			fmt.Fprintf(w, `{"accessToken":"renewed","refreshToken":"refresh","expiresIn":3600}`)
+		}),
+	)
+	defer ts.Close()
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true).(*authService)
+	as.refreshToken = "refresh"
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, as.Validate())
+			assert.Equal(t, "renewed", as.GetToken())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_ValidateCtx_FirstCallerCancels(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			fmt.Fprintf(w, `{"accessToken":"renewed","refreshToken":"refresh","expiresIn":3600}`)
+		}),
+	)
+	defer ts.Close()
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true).(*authService)
+	as.refreshToken = "refresh"
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() { first <- as.ValidateCtx(ctx) }()
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, as.Validate())
+			assert.Equal(t, "renewed", as.GetToken())
+		}()
+	}
+	cancel()
+	assert.Equal(t, context.Canceled, <-first)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func Test_ValidateCtx_ProactiveRenewal(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			fmt.Fprintf(w, `{"accessToken":"renewed","refreshToken":"refresh","expiresIn":600}`)
+		}),
+	)
+	defer ts.Close()
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true).(*authService)
+	as.mu.Lock()
+	as.setToken("current", 600, v2Lifetime)
+	as.refreshToken = "refresh"
+	as.mu.Unlock()
+	assert.Equal(t, as.currentExpiration.Add(-time.Minute), as.renewAt)
+	assert.Nil(t, as.Validate())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+	as.mu.Lock()
+	as.renewAt = time.Now().Add(-time.Second)
+	as.mu.Unlock()
+	assert.Nil(t, as.Validate())
+	waitRenewal(as)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, "renewed", as.GetToken())
+}
+
+func Test_ValidateCtx_ProactiveRenewalBackoff(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			w.WriteHeader(http.StatusInternalServerError)
+		}),
+	)
+	defer ts.Close()
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true).(*authService)
+	as.mu.Lock()
+	as.setToken("current", 600, v2Lifetime)
+	as.refreshToken = "refresh"
+	as.renewAt = time.Now().Add(-time.Second)
+	as.mu.Unlock()
+	// the token is still valid, callers do not wait for the renewal
+	assert.Nil(t, as.Validate())
+	close(release)
+	waitRenewal(as)
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, as.Validate())
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, "current", as.GetToken())
+	as.mu.Lock()
+	assert.WithinDuration(t, time.Now().Add(renewBackoff), as.renewAt, time.Second)
+	as.backoff()
+	assert.WithinDuration(t, time.Now().Add(2*renewBackoff), as.renewAt, time.Second)
+	as.mu.Unlock()
+}
+
+// waitRenewal returns once no renewal of as is running
+func waitRenewal(as *authService) {
+	for {
+		as.mu.RLock()
+		r := as.renewal
+		as.mu.RUnlock()
+		if r == nil {
+			return
+		}
+		<-r.done
+	}
+}
+
+func Test_ValidateCtx_RenewsClientCredentials(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&calls, 1)
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, string(GrantClientCredentials), r.PostForm.Get("grantType"))
+			require.Equal(t, "client", r.PostForm.Get("clientId"))
+			require.Equal(t, "secret", r.PostForm.Get("clientSecret"))
+			fmt.Fprintf(w, `{"accessToken":"token-%d","expiresIn":3600}`, n)
+		}),
+	)
+	defer ts.Close()
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true).(*authService)
+	_, err := as.V2Authenticate(GrantClientCredentials, "", "", "")
+	require.Nil(t, err)
+	assert.Equal(t, "token-1", as.GetToken())
+	as.mu.Lock()
+	as.currentExpiration = time.Now().Add(-time.Second)
+	as.renewAt = as.currentExpiration
+	as.mu.Unlock()
+	assert.Nil(t, as.Validate())
+	assert.Equal(t, "token-2", as.GetToken())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func Test_setToken_DefaultLifetime(t *testing.T) {
+	as := &authService{}
+	as.setToken("token", 0, v2Lifetime)
+	assert.WithinDuration(t, time.Now().Add(v2Lifetime), as.currentExpiration, time.Second)
+	assert.Equal(t, as.currentExpiration.Add(-maxRenewMargin), as.renewAt)
+}
+
+func Test_ValidateCtx_ResumesFromStore(t *testing.T) {
+	var calls int32
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, "stored-refresh", r.PostForm.Get("refreshToken"))
+			fmt.Fprintf(w, `{"accessToken":"renewed","refreshToken":"new-refresh","expiresIn":3600}`)
+		}),
+	)
+	defer ts.Close()
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Save(ctx, "client", Token{AccessToken: "stored", RefreshToken: "stored-refresh", ExpiresAt: time.Now().Add(time.Hour)})
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true, WithTokenStore(store, ""))
+	assert.Nil(t, as.Validate())
+	assert.Equal(t, "stored", as.GetToken())
+	assert.Equal(t, int32(0), atomic.LoadInt32(&calls))
+
+	store.Save(ctx, "client", Token{AccessToken: "stored", RefreshToken: "stored-refresh", ExpiresAt: time.Now().Add(-time.Minute)})
+	as = New(adapter, "client", "secret", true, WithTokenStore(store, ""))
+	assert.Nil(t, as.Validate())
+	assert.Equal(t, "renewed", as.GetToken())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	saved, err := store.Load(ctx, "client")
+	assert.Nil(t, err)
+	assert.Equal(t, "new-refresh", saved.RefreshToken)
+}
+
+func Test_ValidateCtx_RejectedRefreshDeletesStored(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}),
+	)
+	defer ts.Close()
+	ctx := context.Background()
+	store := NewMemoryStore()
+	store.Save(ctx, "merchant-1", Token{RefreshToken: "revoked", ExpiresAt: time.Now().Add(-time.Minute)})
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	as := New(adapter, "client", "secret", true, WithTokenStore(store, "merchant-1"))
+	assert.ErrorIs(t, as.Validate(), ErrUnauthorized)
+	_, err := store.Load(ctx, "merchant-1")
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestV2Auth_StructuredError(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, "client_credentials", r.PostForm.Get("grantType"))
+			require.Empty(t, r.PostForm.Get("refreshToken"))
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":{"code":"Unauthorized","message":"Invalid client credentials"}}`)
+		}),
+	)
+	defer ts.Close()
+	as := New(httpadapter.New(http.DefaultClient, ts.URL), "client", "wrong", true)
+	_, err := as.V2Authenticate(GrantClientCredentials, "", "", "")
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	var apiErr *apierror.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.Status)
+	assert.Equal(t, "Unauthorized", apiErr.Code)
+	assert.Equal(t, "Invalid client credentials", apiErr.Message)
 }
@@ -0,0 +1,143 @@
+package authentication
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrMalformedToken the access token is not a JWT
+var ErrMalformedToken = errors.New("access token is not a JWT")
+
+type (
+	// Claims are the claims of an iFood access token, decoded offline,
+	// the signature is not verified so they must not be trusted for
+	// anything but picking which calls to make
+	Claims struct {
+		Subject   string
+		Issuer    string
+		ClientID  string
+		Type      string
+		Scopes    []string
+		ExpiresAt time.Time
+		IssuedAt  time.Time
+		// MerchantScopes are the scopes granted by each merchant
+		MerchantScopes map[string][]string
+	}
+
+	// jwtClaims is the JWT payload, scope is either a list or
+	// a space separated string, merchant scopes are "id:scope"
+	jwtClaims struct {
+		Subject       string          `json:"sub"`
+		Issuer        string          `json:"iss"`
+		ClientID      string          `json:"client_id"`
+		Type          string          `json:"type"`
+		Scope         json.RawMessage `json:"scope"`
+		MerchantScope []string        `json:"merchant_scope"`
+		ExpiresAt     int64           `json:"exp"`
+		IssuedAt      int64           `json:"iat"`
+	}
+)
+
+// ParseClaims decodes the claims of an access token without
+// verifying its signature
+func ParseClaims(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	var raw jwtClaims
+	if err = json.Unmarshal(payload, &raw); err != nil {
+		return nil, ErrMalformedToken
+	}
+	c := &Claims{
+		Subject:        raw.Subject,
+		Issuer:         raw.Issuer,
+		ClientID:       raw.ClientID,
+		Type:           raw.Type,
+		Scopes:         scopes(raw.Scope),
+		MerchantScopes: make(map[string][]string),
+	}
+	if raw.ExpiresAt > 0 {
+		c.ExpiresAt = time.Unix(raw.ExpiresAt, 0)
+	}
+	if raw.IssuedAt > 0 {
+		c.IssuedAt = time.Unix(raw.IssuedAt, 0)
+	}
+	for _, ms := range raw.MerchantScope {
+		i := strings.LastIndex(ms, ":")
+		if i <= 0 {
+			continue
+		}
+		c.MerchantScopes[ms[:i]] = append(c.MerchantScopes[ms[:i]], ms[i+1:])
+	}
+	return c, nil
+}
+
+func scopes(raw json.RawMessage) []string {
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return strings.Fields(s)
+	}
+	return nil
+}
+
+// HasScope tells whether the token was granted scope
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasMerchantScope tells whether the merchant granted scope
+func (c *Claims) HasMerchantScope(merchantID, scope string) bool {
+	for _, s := range c.MerchantScopes[merchantID] {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Merchants lists the merchants covered by the token
+func (c *Claims) Merchants() (ids []string) {
+	for id := range c.MerchantScopes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return
+}
+
+// Expired tells whether the token is expired at now, a
+// token without expiry never is
+func (c *Claims) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// Scopes splits the space separated Scope
+func (c Credentials) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+func (c Credentials) hasScope(scope string) bool {
+	return (&Claims{Scopes: c.Scopes()}).HasScope(scope)
+}
+
+// Claims decodes the claims of the V2 access token
+func (c V2Credentials) Claims() (*Claims, error) {
+	return ParseClaims(c.AccessToken)
+}
@@ -0,0 +1,96 @@
+package authentication
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testJWT signs nothing, the claims are never verified
+func testJWT(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	require.Nil(t, err)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"RS512","typ":"JWT"}`)) + "." +
+		enc.EncodeToString(payload) + "." + enc.EncodeToString([]byte("signature"))
+}
+
+func TestParseClaims(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Truncate(time.Second)
+	token := testJWT(t, map[string]interface{}{
+		"sub":            "sub",
+		"iss":            "iFood",
+		"client_id":      "client",
+		"type":           "client",
+		"scope":          []string{"merchant", "order", "catalog"},
+		"merchant_scope": []string{"m1:merchant", "m1:catalog", "m2:order", "bogus"},
+		"exp":            exp.Unix(),
+		"iat":            exp.Add(-6 * time.Hour).Unix(),
+	})
+	c, err := ParseClaims(token)
+	require.Nil(t, err)
+	assert.Equal(t, "client", c.ClientID)
+	assert.Equal(t, "iFood", c.Issuer)
+	assert.True(t, exp.Equal(c.ExpiresAt))
+	assert.True(t, exp.Add(-6*time.Hour).Equal(c.IssuedAt))
+	assert.True(t, c.HasScope("catalog"))
+	assert.False(t, c.HasScope("financial"))
+	assert.Equal(t, []string{"m1", "m2"}, c.Merchants())
+	assert.True(t, c.HasMerchantScope("m1", "catalog"))
+	assert.False(t, c.HasMerchantScope("m2", "catalog"))
+	assert.False(t, c.Expired(time.Now()))
+	assert.True(t, c.Expired(exp))
+}
+
+func TestParseClaims_ScopeString(t *testing.T) {
+	c, err := ParseClaims(testJWT(t, map[string]interface{}{"scope": "merchant order"}))
+	require.Nil(t, err)
+	assert.Equal(t, []string{"merchant", "order"}, c.Scopes)
+	assert.False(t, c.Expired(time.Now()))
+}
+
+func TestParseClaims_Malformed(t *testing.T) {
+	for _, token := range []string{"", "opaque", "a.b", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte("[]")) + ".c"} {
+		_, err := ParseClaims(token)
+		assert.Equal(t, ErrMalformedToken, err, token)
+	}
+}
+
+func TestService_Claims(t *testing.T) {
+	token := testJWT(t, map[string]interface{}{
+		"scope":          []string{"merchant"},
+		"merchant_scope": []string{"m1:merchant"},
+	})
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"accessToken":"%s","expiresIn":3600}`, token)
+		}),
+	)
+	defer ts.Close()
+	as := New(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", true)
+	assert.False(t, as.HasScope("merchant"))
+	assert.Nil(t, as.Merchants())
+	_, err := as.V2Authenticate(GrantClientCredentials, "", "", "")
+	require.Nil(t, err)
+	assert.True(t, as.HasScope("merchant"))
+	assert.False(t, as.HasScope("order"))
+	assert.Equal(t, []string{"m1"}, as.Merchants())
+	c, err := as.Claims()
+	require.Nil(t, err)
+	assert.Equal(t, []string{"merchant"}, c.Scopes)
+}
+
+func TestService_HasScopeV1(t *testing.T) {
+	as := &authService{Token: "opaque", scope: "merchant order"}
+	assert.True(t, as.HasScope("order"))
+	assert.False(t, as.HasScope("catalog"))
+	assert.Equal(t, []string{"merchant", "order"}, Credentials{Scope: "merchant order"}.Scopes())
+}
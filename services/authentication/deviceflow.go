@@ -0,0 +1,115 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+)
+
+var (
+	// ErrUserCodeExpired the merchant did not authorize before the user code expired
+	ErrUserCodeExpired = errors.New("user code expired")
+	// ErrAuthorizationDenied the merchant denied the authorization or the code was refused
+	ErrAuthorizationDenied = errors.New("authorization denied")
+	// ErrIncompleteDeviceFlow the DeviceFlow Auth, ShowCode or WaitCode is nil
+	ErrIncompleteDeviceFlow = errors.New("device flow needs Auth, ShowCode and WaitCode")
+)
+
+type (
+	// DeviceFlow drives the distributed authorization of a merchant:
+	// a user code is requested and shown to the merchant, who pastes
+	// back the authorization code before the user code expires, the
+	// code is then exchanged for V2 credentials, saved to the token
+	// store when the service has one
+	DeviceFlow struct {
+		Auth Service
+		// ShowCode surfaces the user code to the merchant,
+		// usually uc.VerificationURLComplete
+		ShowCode func(ctx context.Context, uc *UserCode) error
+		// WaitCode blocks until the merchant pastes back the authorization
+		// code, ctx is done when the user code expires, return
+		// ErrAuthorizationDenied when the merchant refuses
+		WaitCode func(ctx context.Context) (authorizationCode string, err error)
+	}
+
+	// DeviceFlowError matches its Kind, ErrUserCodeExpired or
+	// ErrAuthorizationDenied, with errors.Is and unwraps to the cause
+	DeviceFlowError struct {
+		Kind error
+		Err  error
+	}
+)
+
+// WaitOn returns a DeviceFlow.WaitCode receiving the authorization
+// code from codes, e.g. fed by the handler of a merchant form
+func WaitOn(codes <-chan string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		select {
+		case code := <-codes:
+			return code, nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+}
+
+// Run executes the flow, it fails with a *DeviceFlowError when the
+// user code expires or the authorization is denied
+func (f DeviceFlow) Run(ctx context.Context) (c *V2Credentials, err error) {
+	if f.Auth == nil || f.ShowCode == nil || f.WaitCode == nil {
+		return nil, ErrIncompleteDeviceFlow
+	}
+	uc, err := f.Auth.V2GetUserCodeCtx(ctx)
+	if err != nil {
+		return
+	}
+	expiresAt := time.Now().Add(time.Duration(uc.ExpiresIn) * time.Second)
+	if err = f.ShowCode(ctx, uc); err != nil {
+		return
+	}
+	waitCtx, cancel := context.WithDeadline(ctx, expiresAt)
+	defer cancel()
+	code, err := f.WaitCode(waitCtx)
+	switch {
+	case err == nil && code == "":
+		return nil, &DeviceFlowError{Kind: ErrAuthorizationDenied, Err: errors.New("empty authorization code")}
+	case errors.Is(err, ErrAuthorizationDenied):
+		return nil, &DeviceFlowError{Kind: ErrAuthorizationDenied, Err: err}
+	case err != nil && ctx.Err() == nil && waitCtx.Err() != nil:
+		return nil, &DeviceFlowError{Kind: ErrUserCodeExpired, Err: err}
+	case err != nil:
+		return
+	}
+	c, err = f.Auth.V2AuthenticateCtx(ctx, GrantAuthorizationCode, code, uc.AuthorizationCodeVerifier, "")
+	var apiErr *apierror.APIError
+	if err != nil && errors.As(err, &apiErr) {
+		if time.Now().After(expiresAt) || mentionsExpiry(apiErr) {
+			return nil, &DeviceFlowError{Kind: ErrUserCodeExpired, Err: err}
+		}
+		return nil, &DeviceFlowError{Kind: ErrAuthorizationDenied, Err: err}
+	}
+	return
+}
+
+func mentionsExpiry(e *apierror.APIError) bool {
+	text := strings.ToLower(e.Code + " " + e.Message)
+	return strings.Contains(text, "expired")
+}
+
+func (e *DeviceFlowError) Error() string {
+	return fmt.Sprintf("device flow: %s: %s", e.Kind.Error(), e.Err.Error())
+}
+
+// Is matches the error Kind
+func (e *DeviceFlowError) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the cause
+func (e *DeviceFlowError) Unwrap() error {
+	return e.Err
+}
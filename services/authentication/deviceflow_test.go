@@ -0,0 +1,112 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deviceFlowServer(t *testing.T, expiresIn int, tokenStatus int, tokenBody string) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case authRoot + userCodeEndpoint:
+				fmt.Fprintf(w, `{"userCode":"HJLX-LPSQ","authorizationCodeVerifier":"verifier","verificationUrlComplete":"https://portal.ifood.com.br/apps/code?c=HJLX-LPSQ","expiresIn":%d}`, expiresIn)
+			case authRoot + authEndpoint:
+				require.Nil(t, r.ParseForm())
+				require.Equal(t, "pasted-code", r.PostForm.Get("authorizationCode"))
+				require.Equal(t, "verifier", r.PostForm.Get("authorizationCodeVerifier"))
+				w.WriteHeader(tokenStatus)
+				fmt.Fprint(w, tokenBody)
+			}
+		}),
+	)
+}
+
+func TestDeviceFlow_Run_OK(t *testing.T) {
+	ts := deviceFlowServer(t, 600, http.StatusOK, `{"accessToken":"token","refreshToken":"refresh","expiresIn":3600}`)
+	defer ts.Close()
+	store := NewMemoryStore()
+	as := New(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", true, WithTokenStore(store, "merchant-1"))
+	codes := make(chan string, 1)
+	flow := DeviceFlow{
+		Auth: as,
+		ShowCode: func(ctx context.Context, uc *UserCode) error {
+			assert.Equal(t, "https://portal.ifood.com.br/apps/code?c=HJLX-LPSQ", uc.VerificationURLComplete)
+			codes <- "pasted-code"
+			return nil
+		},
+		WaitCode: WaitOn(codes),
+	}
+	c, err := flow.Run(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "token", c.AccessToken)
+	saved, err := store.Load(context.Background(), "merchant-1")
+	assert.Nil(t, err)
+	assert.Equal(t, "refresh", saved.RefreshToken)
+}
+
+func TestDeviceFlow_Run_Expired(t *testing.T) {
+	ts := deviceFlowServer(t, 1, http.StatusOK, `{}`)
+	defer ts.Close()
+	flow := DeviceFlow{
+		Auth:     New(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", true),
+		ShowCode: func(ctx context.Context, uc *UserCode) error { return nil },
+		WaitCode: WaitOn(make(chan string)),
+	}
+	_, err := flow.Run(context.Background())
+	assert.ErrorIs(t, err, ErrUserCodeExpired)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDeviceFlow_Run_DeniedByMerchant(t *testing.T) {
+	ts := deviceFlowServer(t, 600, http.StatusOK, `{}`)
+	defer ts.Close()
+	flow := DeviceFlow{
+		Auth:     New(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", true),
+		ShowCode: func(ctx context.Context, uc *UserCode) error { return nil },
+		WaitCode: func(ctx context.Context) (string, error) { return "", nil },
+	}
+	_, err := flow.Run(context.Background())
+	assert.ErrorIs(t, err, ErrAuthorizationDenied)
+}
+
+func TestDeviceFlow_Run_CodeRefused(t *testing.T) {
+	ts := deviceFlowServer(t, 600, http.StatusUnauthorized, `{"error":{"code":"Unauthorized","message":"invalid authorization code"}}`)
+	defer ts.Close()
+	flow := DeviceFlow{
+		Auth:     New(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", true),
+		ShowCode: func(ctx context.Context, uc *UserCode) error { return nil },
+		WaitCode: func(ctx context.Context) (string, error) { return "pasted-code", nil },
+	}
+	_, err := flow.Run(context.Background())
+	assert.ErrorIs(t, err, ErrAuthorizationDenied)
+	var apiErr *apierror.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "invalid authorization code", apiErr.Message)
+}
+
+func TestDeviceFlow_Run_CodeExpiredByAPI(t *testing.T) {
+	ts := deviceFlowServer(t, 600, http.StatusBadRequest, `{"error":{"code":"BadRequest","message":"Authorization code expired"}}`)
+	defer ts.Close()
+	flow := DeviceFlow{
+		Auth:     New(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", true),
+		ShowCode: func(ctx context.Context, uc *UserCode) error { return nil },
+		WaitCode: func(ctx context.Context) (string, error) { return "pasted-code", nil },
+	}
+	_, err := flow.Run(context.Background())
+	assert.ErrorIs(t, err, ErrUserCodeExpired)
+}
+
+func TestDeviceFlow_Run_Incomplete(t *testing.T) {
+	_, err := DeviceFlow{}.Run(context.Background())
+	assert.Equal(t, ErrIncompleteDeviceFlow, err)
+}
@@ -0,0 +1,131 @@
+package authentication
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrInvalidStoreKey the file store key is not 16, 24 or 32 bytes long
+var ErrInvalidStoreKey = errors.New("file store key should be 16, 24 or 32 bytes long")
+
+// ErrCorruptStore the file store could not be decrypted with the key
+var ErrCorruptStore = errors.New("file store could not be decrypted, wrong key or corrupt file")
+
+type fileStore struct {
+	mu   sync.Mutex
+	path string
+	aead cipher.AEAD
+}
+
+// NewFileStore returns a TokenStore saving every token in a single
+// file encrypted with AES-GCM, key selects AES-128, 192 or 256
+func NewFileStore(path string, key []byte) (TokenStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ErrInvalidStoreKey
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &fileStore{path: path, aead: aead}, nil
+}
+
+func (f *fileStore) Load(ctx context.Context, key string) (Token, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return Token{}, err
+	}
+	t, ok := tokens[key]
+	if !ok {
+		return Token{}, ErrTokenNotFound
+	}
+	return t, nil
+}
+
+func (f *fileStore) Save(ctx context.Context, key string, token Token) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = token
+	return f.write(tokens)
+}
+
+func (f *fileStore) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	tokens, err := f.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return f.write(tokens)
+}
+
+// read decrypts the file, a missing file is an empty store
+func (f *fileStore) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	size := f.aead.NonceSize()
+	if len(data) < size {
+		return nil, ErrCorruptStore
+	}
+	plain, err := f.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, ErrCorruptStore
+	}
+	if err = json.Unmarshal(plain, &tokens); err != nil {
+		return nil, ErrCorruptStore
+	}
+	return tokens, nil
+}
+
+// write encrypts the tokens with a fresh nonce and replaces the
+// file atomically, TempFile creates it readable by the owner only
+func (f *fileStore) write(tokens map[string]Token) error {
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, f.aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	data := f.aead.Seal(nonce, nonce, plain, nil)
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
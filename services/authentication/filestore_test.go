@@ -0,0 +1,62 @@
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tempStorePath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ifood-store")
+	require.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return filepath.Join(dir, "tokens")
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := tempStorePath(t)
+	key := bytes.Repeat([]byte("k"), 32)
+	store, err := NewFileStore(path, key)
+	require.Nil(t, err)
+	_, err = store.Load(ctx, "client")
+	assert.Equal(t, ErrTokenNotFound, err)
+	token := Token{AccessToken: "access-secret", RefreshToken: "refresh-secret", ExpiresAt: time.Now().UTC().Truncate(time.Second)}
+	require.Nil(t, store.Save(ctx, "client", token))
+	data, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	assert.False(t, bytes.Contains(data, []byte("secret")))
+	reopened, err := NewFileStore(path, key)
+	require.Nil(t, err)
+	loaded, err := reopened.Load(ctx, "client")
+	assert.Nil(t, err)
+	assert.True(t, token.ExpiresAt.Equal(loaded.ExpiresAt))
+	assert.Equal(t, token.RefreshToken, loaded.RefreshToken)
+	assert.Nil(t, reopened.Delete(ctx, "client"))
+	_, err = reopened.Load(ctx, "client")
+	assert.Equal(t, ErrTokenNotFound, err)
+}
+
+func TestFileStore_WrongKey(t *testing.T) {
+	ctx := context.Background()
+	path := tempStorePath(t)
+	store, err := NewFileStore(path, bytes.Repeat([]byte("a"), 16))
+	require.Nil(t, err)
+	require.Nil(t, store.Save(ctx, "client", Token{AccessToken: "access"}))
+	other, err := NewFileStore(path, bytes.Repeat([]byte("b"), 16))
+	require.Nil(t, err)
+	_, err = other.Load(ctx, "client")
+	assert.Equal(t, ErrCorruptStore, err)
+}
+
+func TestNewFileStore_InvalidKey(t *testing.T) {
+	_, err := NewFileStore(tempStorePath(t), []byte("short"))
+	assert.Equal(t, ErrInvalidStoreKey, err)
+}
@@ -0,0 +1,59 @@
+package authentication
+
+import "net/url"
+
+// GrantType is the OAuth grant of a V2 token request
+type GrantType string
+
+const (
+	// GrantClientCredentials authenticates the application itself
+	GrantClientCredentials GrantType = "client_credentials"
+	// GrantAuthorizationCode exchanges the code pasted back by the merchant
+	GrantAuthorizationCode GrantType = "authorization_code"
+	// GrantRefreshToken renews an access token
+	GrantRefreshToken GrantType = "refresh_token"
+)
+
+// Valid tells whether g is one of the V2 grants
+func (g GrantType) Valid() bool {
+	return g == GrantClientCredentials || g == GrantAuthorizationCode || g == GrantRefreshToken
+}
+
+// tokenForm returns the form of a V2 token request, each grant
+// sends only the fields it needs
+func (a *authService) tokenForm(grant GrantType, authCode, authCodeVerifier, refreshToken string) url.Values {
+	switch grant {
+	case GrantAuthorizationCode:
+		return a.authorizationCodeForm(authCode, authCodeVerifier)
+	case GrantRefreshToken:
+		return a.refreshTokenForm(refreshToken)
+	}
+	return a.clientCredentialsForm()
+}
+
+func (a *authService) clientCredentialsForm() url.Values {
+	form := url.Values{}
+	form.Set("grantType", string(GrantClientCredentials))
+	form.Set("clientId", a.clientId)
+	form.Set("clientSecret", a.clientSecret)
+	return form
+}
+
+func (a *authService) authorizationCodeForm(authCode, authCodeVerifier string) url.Values {
+	form := url.Values{}
+	form.Set("grantType", string(GrantAuthorizationCode))
+	form.Set("clientId", a.clientId)
+	form.Set("clientSecret", a.clientSecret)
+	form.Set("authorizationCode", authCode)
+	form.Set("authorizationCodeVerifier", authCodeVerifier)
+	return form
+}
+
+func (a *authService) refreshTokenForm(refreshToken string) url.Values {
+	form := url.Values{}
+	form.Set("grantType", string(GrantRefreshToken))
+	form.Set("clientId", a.clientId)
+	form.Set("clientSecret", a.clientSecret)
+	form.Set("refreshToken", refreshToken)
+	return form
+}
@@ -0,0 +1,35 @@
+package authentication
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrantType_Valid(t *testing.T) {
+	assert.True(t, GrantClientCredentials.Valid())
+	assert.True(t, GrantAuthorizationCode.Valid())
+	assert.True(t, GrantRefreshToken.Valid())
+	assert.False(t, GrantType("password").Valid())
+}
+
+func Test_tokenForm(t *testing.T) {
+	as := &authService{clientId: "client", clientSecret: "secret"}
+	cases := map[GrantType]url.Values{
+		GrantClientCredentials: {
+			"grantType": {"client_credentials"}, "clientId": {"client"}, "clientSecret": {"secret"},
+		},
+		GrantAuthorizationCode: {
+			"grantType": {"authorization_code"}, "clientId": {"client"}, "clientSecret": {"secret"},
+			"authorizationCode": {"code"}, "authorizationCodeVerifier": {"verifier"},
+		},
+		GrantRefreshToken: {
+			"grantType": {"refresh_token"}, "clientId": {"client"}, "clientSecret": {"secret"},
+			"refreshToken": {"refresh"},
+		},
+	}
+	for grant, want := range cases {
+		assert.Equal(t, want, as.tokenForm(grant, "code", "verifier", "refresh"), string(grant))
+	}
+}
@@ -0,0 +1,179 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/arxdsilva/golang-ifood-sdk/adapters"
+)
+
+// ErrUnknownMerchant the manager has no credentials linked to the merchant
+var ErrUnknownMerchant = errors.New("merchant has no linked credentials")
+
+// ErrMerchantRequired the call acts on behalf of a merchant and the ctx has
+// none, set it with WithMerchant
+var ErrMerchantRequired = errors.New("merchant is required, set it with WithMerchant")
+
+type (
+	// Resolver picks the credentials of a merchant, services given a
+	// Resolver as their auth Service use it on every request
+	Resolver interface {
+		ForMerchant(ctx context.Context, merchantID string) (Service, error)
+	}
+
+	// Manager keeps the credentials of many merchants, each linked through
+	// its own authorization code and renewed independently, calls without
+	// a merchant use the embedded application credentials
+	//
+	// Manager is a Service, hand it to the merchant, catalog and orders
+	// services so they resolve the merchant token on every request, the
+	// orders calls fail with ErrMerchantRequired without a merchant
+	Manager struct {
+		Service
+		adapter                adapters.Http
+		clientId, clientSecret string
+		store                  TokenStore
+		opts                   []Option
+		mu                     sync.RWMutex
+		tenants                map[string]Service
+	}
+
+	merchantKey struct{}
+)
+
+// NewManager returns a V2 credential manager, the tokens of every
+// merchant are kept in store, in memory when store is nil
+func NewManager(adapter adapters.Http, clientId, clientSecret string, store TokenStore, opts ...Option) *Manager {
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	return &Manager{
+		Service:      New(adapter, clientId, clientSecret, true, opts...),
+		adapter:      adapter,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		store:        store,
+		opts:         opts,
+		tenants:      make(map[string]Service),
+	}
+}
+
+// Tenant returns the auth service of the merchant, creating it when
+// needed, use it as the DeviceFlow Auth to link a merchant
+func (m *Manager) Tenant(merchantID string) Service {
+	m.mu.RLock()
+	t, ok := m.tenants[merchantID]
+	m.mu.RUnlock()
+	if ok {
+		return t
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if t, ok = m.tenants[merchantID]; ok {
+		return t
+	}
+	opts := append(append([]Option{}, m.opts...), WithTokenStore(m.store, m.storeKey(merchantID)))
+	tenant := New(m.adapter, m.clientId, m.clientSecret, true, opts...).(*authService)
+	// merchants are linked with their code, never with the application credentials
+	tenant.grant = GrantAuthorizationCode
+	t = tenant
+	m.tenants[merchantID] = t
+	return t
+}
+
+// Link exchanges the authorization code pasted back by the merchant
+// for its credentials
+func (m *Manager) Link(ctx context.Context, merchantID, authCode, authCodeVerifier string) (err error) {
+	_, err = m.Tenant(merchantID).V2AuthenticateCtx(ctx, GrantAuthorizationCode, authCode, authCodeVerifier, "")
+	return
+}
+
+// ForMerchant returns the auth service of a linked merchant, either in
+// this process or in the token store, ErrUnknownMerchant otherwise
+func (m *Manager) ForMerchant(ctx context.Context, merchantID string) (Service, error) {
+	m.mu.RLock()
+	t, ok := m.tenants[merchantID]
+	m.mu.RUnlock()
+	if ok && t.GetToken() != "" {
+		return t, nil
+	}
+	_, err := m.store.Load(ctx, m.storeKey(merchantID))
+	if errors.Is(err, ErrTokenNotFound) {
+		return nil, ErrUnknownMerchant
+	}
+	if err != nil {
+		return nil, err
+	}
+	return m.Tenant(merchantID), nil
+}
+
+// Remove forgets the credentials of the merchant
+func (m *Manager) Remove(ctx context.Context, merchantID string) error {
+	m.mu.Lock()
+	delete(m.tenants, merchantID)
+	m.mu.Unlock()
+	return m.store.Delete(ctx, m.storeKey(merchantID))
+}
+
+// Tenants lists the merchants with an auth service in this process
+func (m *Manager) Tenants() (ids []string) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for id := range m.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return
+}
+
+func (m *Manager) storeKey(merchantID string) string {
+	return m.clientId + "/" + merchantID
+}
+
+// Invalidate drops the application token when the API refused it
+func (m *Manager) Invalidate(token string) {
+	if inv, ok := m.Service.(invalidator); ok {
+		inv.Invalidate(token)
+	}
+}
+
+// WithMerchant returns a ctx carrying the merchant id, used to pick the
+// credentials of calls that do not receive one, e.g. orders, ctx is
+// returned as is when merchantID is empty
+func WithMerchant(ctx context.Context, merchantID string) context.Context {
+	if merchantID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, merchantKey{}, merchantID)
+}
+
+// MerchantFromContext returns the merchant id set with WithMerchant
+func MerchantFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(merchantKey{}).(string)
+	return id
+}
+
+// Bearer validates the credentials of the merchant and returns its token,
+// the merchant in ctx is used when merchantID is empty, auth is used
+// as is unless it is a Resolver
+func Bearer(ctx context.Context, auth Service, merchantID string) (token string, err error) {
+	if auth, err = resolve(ctx, auth, merchantID); err != nil {
+		return
+	}
+	if err = auth.ValidateCtx(ctx); err != nil {
+		return
+	}
+	return auth.GetToken(), nil
+}
+
+func resolve(ctx context.Context, auth Service, merchantID string) (Service, error) {
+	if merchantID == "" {
+		merchantID = MerchantFromContext(ctx)
+	}
+	if r, ok := auth.(Resolver); ok && merchantID != "" {
+		return r.ForMerchant(ctx, merchantID)
+	}
+	return auth, nil
+}
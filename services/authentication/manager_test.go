@@ -0,0 +1,96 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBearer_PlainService(t *testing.T) {
+	am := &AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	token, err := Bearer(WithMerchant(context.Background(), "merchant"), am, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "token", token)
+	am.AssertExpectations(t)
+}
+
+func TestMerchantFromContext(t *testing.T) {
+	assert.Equal(t, "", MerchantFromContext(context.Background()))
+	assert.Equal(t, "merchant", MerchantFromContext(WithMerchant(context.Background(), "merchant")))
+}
+
+func TestManager_RenewsEachMerchant(t *testing.T) {
+	var mu sync.Mutex
+	var refreshed []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Nil(t, r.ParseForm())
+			mu.Lock()
+			defer mu.Unlock()
+			if refresh := r.PostForm.Get("refreshToken"); refresh != "" {
+				refreshed = append(refreshed, refresh)
+				fmt.Fprintf(w, `{"accessToken":"renewed-%s","refreshToken":"%s","expiresIn":3600}`, refresh, refresh)
+				return
+			}
+			code := r.PostForm.Get("authorizationCode")
+			fmt.Fprintf(w, `{"accessToken":"access-%s","refreshToken":"refresh-%s","expiresIn":3600}`, code, code)
+		}),
+	)
+	defer ts.Close()
+	ctx := context.Background()
+	store := NewMemoryStore()
+	m := NewManager(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", store)
+	require.Nil(t, m.Link(ctx, "a", "a", "verifier"))
+	require.Nil(t, m.Link(ctx, "b", "b", "verifier"))
+	assert.Equal(t, []string{"a", "b"}, m.Tenants())
+
+	expired := Token{AccessToken: "old", RefreshToken: "refresh-a", ExpiresAt: time.Now().Add(-time.Minute)}
+	require.Nil(t, store.Save(ctx, "client/a", expired))
+	restarted := NewManager(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", store)
+	token, err := Bearer(ctx, restarted, "a")
+	assert.Nil(t, err)
+	assert.Equal(t, "renewed-refresh-a", token)
+	token, err = Bearer(ctx, restarted, "b")
+	assert.Nil(t, err)
+	assert.Equal(t, "access-b", token)
+	assert.Equal(t, []string{"refresh-a"}, refreshed)
+
+	_, err = Bearer(ctx, restarted, "c")
+	assert.Equal(t, ErrUnknownMerchant, err)
+	require.Nil(t, restarted.Remove(ctx, "b"))
+	_, err = Bearer(ctx, restarted, "b")
+	assert.Equal(t, ErrUnknownMerchant, err)
+}
+
+func TestManager_RenewsApplicationToken(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			require.Nil(t, r.ParseForm())
+			require.Equal(t, string(GrantClientCredentials), r.PostForm.Get("grantType"))
+			fmt.Fprintf(w, `{"accessToken":"app-%d","expiresIn":3600}`, calls)
+		}),
+	)
+	defer ts.Close()
+	m := NewManager(httpadapter.New(http.DefaultClient, ts.URL), "client", "secret", NewMemoryStore())
+	require.Nil(t, m.Validate())
+	assert.Equal(t, "app-1", m.GetToken())
+	app := m.Service.(*authService)
+	app.mu.Lock()
+	app.currentExpiration = time.Now().Add(-time.Second)
+	app.renewAt = app.currentExpiration
+	app.mu.Unlock()
+	require.Nil(t, m.Validate())
+	assert.Equal(t, "app-2", m.GetToken())
+}
@@ -1,6 +1,8 @@
 package authentication
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 )
 
@@ -18,14 +20,25 @@ func (a *AuthMock) V2GetUserCode() (uc *UserCode, err error) {
 	return nil, args.Error(1)
 }
 
-func (a *AuthMock) V2Authenticate(authType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error) {
-	args := a.Called(authType, authCode, authCodeVerifier, refreshToken)
+// V2GetUserCodeCtx mock of auth service, shares the V2GetUserCode expectations
+func (a *AuthMock) V2GetUserCodeCtx(ctx context.Context) (uc *UserCode, err error) {
+	return a.V2GetUserCode()
+}
+
+// V2Authenticate mock of auth service, the grant is matched as a string
+func (a *AuthMock) V2Authenticate(authType GrantType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error) {
+	args := a.Called(string(authType), authCode, authCodeVerifier, refreshToken)
 	if res, ok := args.Get(0).(*V2Credentials); ok {
 		return res, nil
 	}
 	return nil, args.Error(1)
 }
 
+// V2AuthenticateCtx mock of auth service, shares the V2Authenticate expectations
+func (a *AuthMock) V2AuthenticateCtx(ctx context.Context, authType GrantType, authCode, authCodeVerifier, refreshToken string) (c *V2Credentials, err error) {
+	return a.V2Authenticate(authType, authCode, authCodeVerifier, refreshToken)
+}
+
 // Authenticate mock of auth service
 func (a *AuthMock) Authenticate(user, pass string) (c *Credentials, err error) {
 	args := a.Called(user, pass)
@@ -35,14 +48,46 @@ func (a *AuthMock) Authenticate(user, pass string) (c *Credentials, err error) {
 	return nil, args.Error(1)
 }
 
+// AuthenticateCtx mock of auth service, shares the Authenticate expectations
+func (a *AuthMock) AuthenticateCtx(ctx context.Context, user, pass string) (c *Credentials, err error) {
+	return a.Authenticate(user, pass)
+}
+
 // Validate mock of auth service
 func (a *AuthMock) Validate() (err error) {
 	args := a.Called()
 	return args.Error(0)
 }
 
+// ValidateCtx mock of auth service, shares the Validate expectations
+func (a *AuthMock) ValidateCtx(ctx context.Context) (err error) {
+	return a.Validate()
+}
+
 // GetToken mock of auth service
 func (a *AuthMock) GetToken() (token string) {
 	args := a.Called()
 	return args.Get(0).(string)
 }
+
+// Claims mock of auth service
+func (a *AuthMock) Claims() (c *Claims, err error) {
+	args := a.Called()
+	if res, ok := args.Get(0).(*Claims); ok {
+		return res, nil
+	}
+	return nil, args.Error(1)
+}
+
+// HasScope mock of auth service
+func (a *AuthMock) HasScope(scope string) bool {
+	args := a.Called(scope)
+	return args.Bool(0)
+}
+
+// Merchants mock of auth service
+func (a *AuthMock) Merchants() []string {
+	args := a.Called()
+	ids, _ := args.Get(0).([]string)
+	return ids
+}
@@ -0,0 +1,66 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrTokenNotFound the store has no token under the key
+var ErrTokenNotFound = errors.New("token not found")
+
+type (
+	// TokenStore persists the V2 credentials so a restarted service
+	// resumes from the stored refresh token, implementations must
+	// be safe for concurrent use
+	TokenStore interface {
+		// Load returns ErrTokenNotFound when there is no token under key
+		Load(ctx context.Context, key string) (Token, error)
+		Save(ctx context.Context, key string, token Token) error
+		Delete(ctx context.Context, key string) error
+	}
+
+	// Token is a stored credential
+	Token struct {
+		AccessToken  string    `json:"accessToken"`
+		RefreshToken string    `json:"refreshToken"`
+		ExpiresAt    time.Time `json:"expiresAt"`
+		// Grant produced the credentials, renewals depend on it
+		Grant GrantType `json:"grant,omitempty"`
+	}
+
+	memoryStore struct {
+		mu     sync.RWMutex
+		tokens map[string]Token
+	}
+)
+
+// NewMemoryStore returns a TokenStore kept in memory
+func NewMemoryStore() TokenStore {
+	return &memoryStore{tokens: make(map[string]Token)}
+}
+
+func (m *memoryStore) Load(ctx context.Context, key string) (Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	t, ok := m.tokens[key]
+	if !ok {
+		return Token{}, ErrTokenNotFound
+	}
+	return t, nil
+}
+
+func (m *memoryStore) Save(ctx context.Context, key string, token Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tokens[key] = token
+	return nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tokens, key)
+	return nil
+}
@@ -0,0 +1,24 @@
+package authentication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	_, err := store.Load(ctx, "client")
+	assert.Equal(t, ErrTokenNotFound, err)
+	token := Token{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: time.Now()}
+	assert.Nil(t, store.Save(ctx, "client", token))
+	loaded, err := store.Load(ctx, "client")
+	assert.Nil(t, err)
+	assert.Equal(t, token, loaded)
+	assert.Nil(t, store.Delete(ctx, "client"))
+	_, err = store.Load(ctx, "client")
+	assert.Equal(t, ErrTokenNotFound, err)
+}
@@ -0,0 +1,169 @@
+package authentication
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/arxdsilva/golang-ifood-sdk/adapters"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+type (
+	// Transport is an http.RoundTripper authenticating requests with the
+	// bearer token of Auth, the merchant token when the request ctx was
+	// given one with WithMerchant, a request refused with 401 or 403 is
+	// sent once more after the token is renewed
+	Transport struct {
+		Auth Service
+		// Base sends the requests, http.DefaultTransport when nil
+		Base http.RoundTripper
+	}
+
+	// authorizedAdapter is the adapters.Http counterpart of Transport
+	authorizedAdapter struct {
+		adapter adapters.Http
+		auth    authorizer
+	}
+
+	// authorizer is the core shared by Transport and Authorize,
+	// requireMerchant refuses calls without a merchant when auth
+	// is a Resolver
+	authorizer struct {
+		auth            Service
+		requireMerchant bool
+	}
+
+	// invalidator drops a token refused by the API, so the next
+	// Validate renews it
+	invalidator interface {
+		Invalidate(token string)
+	}
+)
+
+// NewTransport returns a Transport over base, e.g. for an http.Client
+// calling iFood endpoints the SDK does not cover yet
+func NewTransport(auth Service, base http.RoundTripper) *Transport {
+	return &Transport{Auth: auth, Base: base}
+}
+
+// RoundTrip implements http.RoundTripper, a request with a body
+// is only sent again when it has a GetBody
+func (t *Transport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	attempt := 0
+	err = authorizer{auth: t.Auth}.do(req.Context(), func(token string) (int, error) {
+		if attempt++; attempt > 1 {
+			if req.Body != nil && req.GetBody == nil {
+				return 0, nil
+			}
+			drain(resp.Body)
+		}
+		r := req.Clone(req.Context())
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return 0, err
+			}
+			r.Body = body
+		}
+		r.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		if resp, err = base.RoundTrip(r); err != nil {
+			return 0, err
+		}
+		return resp.StatusCode, nil
+	})
+	if err != nil && resp != nil {
+		drain(resp.Body)
+		resp = nil
+	}
+	return
+}
+
+// Authorize wraps adapter so every request carries the bearer token of
+// auth, see Transport, the merchant is taken from the request ctx
+func Authorize(adapter adapters.Http, auth Service) adapters.Http {
+	return &authorizedAdapter{adapter: adapter, auth: authorizer{auth: auth}}
+}
+
+// AuthorizeMerchant works like Authorize for the calls that act on behalf
+// of a merchant, when auth is a Resolver such as a Manager a request ctx
+// without a merchant fails with ErrMerchantRequired instead of using the
+// application token
+func AuthorizeMerchant(adapter adapters.Http, auth Service) adapters.Http {
+	return &authorizedAdapter{adapter: adapter, auth: authorizer{auth: auth, requireMerchant: true}}
+}
+
+func (a *authorizedAdapter) DoRequest(method, path string, reader io.Reader, headers map[string]string) ([]byte, int, error) {
+	return a.DoRequestCtx(context.Background(), method, path, reader, headers)
+}
+
+// DoRequestCtx buffers the reader so a refused request can be sent again
+func (a *authorizedAdapter) DoRequestCtx(ctx context.Context, method, path string, reader io.Reader, headers map[string]string) (response []byte, status int, err error) {
+	var body []byte
+	if reader != nil {
+		if body, err = ioutil.ReadAll(reader); err != nil {
+			return
+		}
+	}
+	err = a.auth.do(ctx, func(token string) (int, error) {
+		h := make(map[string]string, len(headers)+1)
+		for k, v := range headers {
+			h[k] = v
+		}
+		h["Authorization"] = fmt.Sprintf("Bearer %s", token)
+		var r io.Reader
+		if reader != nil {
+			r = bytes.NewReader(body)
+		}
+		response, status, err = a.adapter.DoRequestCtx(ctx, method, path, r, h)
+		return status, err
+	})
+	return
+}
+
+// Logger keeps the services logging through the wrapped adapter
+func (a *authorizedAdapter) Logger() logger.Logger {
+	return logger.Of(a.adapter)
+}
+
+// do sends the request with the current token, once more with a
+// renewed token when the API refuses it and the service can drop it
+func (a authorizer) do(ctx context.Context, send func(token string) (int, error)) error {
+	if _, ok := a.auth.(Resolver); ok && a.requireMerchant && MerchantFromContext(ctx) == "" {
+		return ErrMerchantRequired
+	}
+	svc, err := resolve(ctx, a.auth, "")
+	if err != nil {
+		return err
+	}
+	if err = svc.ValidateCtx(ctx); err != nil {
+		return err
+	}
+	token := svc.GetToken()
+	status, err := send(token)
+	if err != nil || (status != http.StatusUnauthorized && status != http.StatusForbidden) {
+		return err
+	}
+	inv, ok := svc.(invalidator)
+	if !ok {
+		return nil
+	}
+	inv.Invalidate(token)
+	if err = svc.ValidateCtx(ctx); err != nil {
+		return err
+	}
+	_, err = send(svc.GetToken())
+	return err
+}
+
+func drain(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
+}
@@ -0,0 +1,89 @@
+package authentication
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// refusingServer accepts only the renewed token, refreshes counts the renewals
+func refusingServer(t *testing.T, status int, refreshes *int, bodies *[]string) *httptest.Server {
+	return httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == authRoot+authEndpoint {
+				*refreshes++
+				fmt.Fprint(w, `{"accessToken":"renewed","refreshToken":"refresh","expiresIn":3600}`)
+				return
+			}
+			body, err := ioutil.ReadAll(r.Body)
+			require.Nil(t, err)
+			*bodies = append(*bodies, string(body))
+			if r.Header.Get("Authorization") != "Bearer renewed" {
+				w.WriteHeader(status)
+				return
+			}
+			fmt.Fprint(w, `ok`)
+		}),
+	)
+}
+
+func storedService(t *testing.T, url string) Service {
+	store := NewMemoryStore()
+	token := Token{AccessToken: "stale", RefreshToken: "refresh", ExpiresAt: time.Now().Add(time.Hour)}
+	require.Nil(t, store.Save(context.Background(), "client", token))
+	return New(httpadapter.New(http.DefaultClient, url), "client", "secret", true, WithTokenStore(store, ""))
+}
+
+func TestTransport_RenewsOnUnauthorized(t *testing.T) {
+	var refreshes int
+	var bodies []string
+	ts := refusingServer(t, http.StatusUnauthorized, &refreshes, &bodies)
+	defer ts.Close()
+	client := &http.Client{Transport: NewTransport(storedService(t, ts.URL), nil)}
+	resp, err := client.Post(ts.URL+"/order/v1.0/orders/1/confirm", "text/plain", strings.NewReader("payload"))
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, refreshes)
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+}
+
+func TestTransport_NoInvalidator(t *testing.T) {
+	var refreshes int
+	var bodies []string
+	ts := refusingServer(t, http.StatusForbidden, &refreshes, &bodies)
+	defer ts.Close()
+	am := &AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	client := &http.Client{Transport: NewTransport(am, nil)}
+	resp, err := client.Get(ts.URL + "/merchant/v1.0/merchants")
+	require.Nil(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Equal(t, 0, refreshes)
+	am.AssertExpectations(t)
+}
+
+func TestAuthorize_RenewsOnForbidden(t *testing.T) {
+	var refreshes int
+	var bodies []string
+	ts := refusingServer(t, http.StatusForbidden, &refreshes, &bodies)
+	defer ts.Close()
+	adapter := Authorize(httpadapter.New(http.DefaultClient, ts.URL), storedService(t, ts.URL))
+	resp, status, err := adapter.DoRequest(http.MethodPost, "/catalog/v2.0/merchants/1/products", strings.NewReader("product"), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", string(resp))
+	assert.Equal(t, 1, refreshes)
+	assert.Equal(t, []string{"product", "product"}, bodies)
+}
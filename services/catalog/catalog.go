@@ -1,14 +1,16 @@
 package catalog
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
-	"github.com/kpango/glg"
 )
 
 const (
@@ -17,41 +19,40 @@ const (
 
 type catalogService struct {
 	adapter adapters.Http
-	auth    auth.Service
+	log     logger.Logger
 }
 
 // New returns an implementation of the catalog service
 func New(adapter adapters.Http, authService auth.Service) *catalogService {
-	return &catalogService{adapter, authService}
+	return &catalogService{auth.Authorize(adapter, authService), logger.Of(adapter)}
 }
 
 // ListAll catalogs from a Merchant
 func (c *catalogService) ListAllV2(merchantUUID string) (ct Catalogs, err error) {
+	return c.ListAllV2Ctx(context.Background(), merchantUUID)
+}
+
+// ListAllV2Ctx works like ListAllV2, honoring ctx cancellation and deadlines
+func (c *catalogService) ListAllV2Ctx(ctx context.Context, merchantUUID string) (ct Catalogs, err error) {
 	if merchantUUID == "" {
 		err = ErrMerchantNotSpecified
-		glg.Error("[SDK] Catalog ListAll: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog ListAll auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog ListAll", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/catalogs", merchantUUID)
-	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog ListAll adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog ListAll adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog ListAll status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not list catalogs", merchantUUID)
-		glg.Error("[SDK] Catalog ListAll err: ", err)
+		c.log.Error("[SDK] Catalog ListAll", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not list catalogs", merchantUUID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog ListAll err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] ListAll catalogs success")
+	c.log.Info("[SDK] ListAll catalogs success")
 	return ct, json.Unmarshal(resp, &ct)
 }
 
@@ -62,33 +63,32 @@ func (c *catalogService) ListChangelogs(merchantUUID string) (ct Catalogs, err e
 
 // ListUnsellableItems returns all blocked sellable items and why
 func (c *catalogService) ListUnsellableItems(merchantUUID, catalogID string) (ur UnsellableResponse, err error) {
+	return c.ListUnsellableItemsCtx(context.Background(), merchantUUID, catalogID)
+}
+
+// ListUnsellableItemsCtx works like ListUnsellableItems, honoring ctx cancellation and deadlines
+func (c *catalogService) ListUnsellableItemsCtx(ctx context.Context, merchantUUID, catalogID string) (ur UnsellableResponse, err error) {
 	if err = verifyCategoryItems(merchantUUID, catalogID, "category"); err != nil {
-		glg.Error("[SDK] Catalog ListUnsellableItems: ", err.Error())
+		c.log.Error("[SDK] Catalog ListUnsellableItems", logger.Err(err))
 		return
 	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog ListUnsellableItems auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/catalogs/%s/unsellable-items", merchantUUID, catalogID)
-	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog ListUnsellableItems adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog ListUnsellableItems adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog ListUnsellableItems status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog ListUnsellableItems", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not list unsellable items, catalog: '%s'",
-			merchantUUID, catalogID)
-		glg.Error("[SDK] Catalog ListUnsellableItems err: ", err)
+			merchantUUID, catalogID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog ListUnsellableItems err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] List Unsellable Items success")
+	c.log.Info("[SDK] List Unsellable Items success")
 	return ur, json.Unmarshal(resp, &ur)
 }
 
@@ -1,43 +1,45 @@
 package catalog
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
-	"github.com/kpango/glg"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 )
 
 // ListAllCategoriesInCatalog gets categories in a catalog
 func (c *catalogService) ListAllCategoriesInCatalog(merchantUUID, catalogID string) (cr CategoryResponse, err error) {
+	return c.ListAllCategoriesInCatalogCtx(context.Background(), merchantUUID, catalogID)
+}
+
+// ListAllCategoriesInCatalogCtx works like ListAllCategoriesInCatalog, honoring ctx cancellation and deadlines
+func (c *catalogService) ListAllCategoriesInCatalogCtx(ctx context.Context, merchantUUID, catalogID string) (cr CategoryResponse, err error) {
 	if err = verifyCategoryItems(merchantUUID, catalogID, "category"); err != nil {
-		glg.Error("[SDK] Catalog ListAllCategoriesInCatalog: ", err.Error())
+		c.log.Error("[SDK] Catalog ListAllCategoriesInCatalog", logger.Err(err))
 		return
 	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog ListAllCategoriesInCatalog auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/catalogs/%s/categories", merchantUUID, catalogID)
-	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog ListAllCategoriesInCatalog adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog ListAllCategoriesInCatalog adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog ListAllCategoriesInCatalog status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog ListAllCategoriesInCatalog", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not list categories in catalog '%s'",
-			merchantUUID, catalogID)
-		glg.Error("[SDK] Catalog ListAllCategoriesInCatalog err: ", err)
+			merchantUUID, catalogID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog ListAllCategoriesInCatalog err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] ListAll Categories success")
+	c.log.Info("[SDK] ListAll Categories success")
 	return cr, json.Unmarshal(resp, &cr)
 }
 
@@ -47,73 +49,72 @@ func (c *catalogService) ListAllCategoriesInCatalog(merchantUUID, catalogID stri
 // template 		= [DEFAULT	 ||	PIZZA]
 //
 func (c *catalogService) CreateCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (cr CategoryCreateResponse, err error) {
+	return c.CreateCategoryInCatalogCtx(context.Background(), merchantUUID, catalogID, name, resourceStatus, template, externalCode)
+}
+
+// CreateCategoryInCatalogCtx works like CreateCategoryInCatalog, honoring ctx cancellation and deadlines
+func (c *catalogService) CreateCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (cr CategoryCreateResponse, err error) {
 	err = verifyNewCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateCategoryInCatalog verifyNewCategoryInCatalog: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog CreateCategoryInCatalog auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateCategoryInCatalog verifyNewCategoryInCatalog", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/catalogs/%s/categories", merchantUUID, catalogID)
 	ci := CategoryItem{Name: name, Status: resourceStatus, Template: template, ExternalCode: externalCode}
 	reader, err := httpadapter.NewJsonReader(ci)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateCategoryInCatalog NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateCategoryInCatalog NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, reader, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateCategoryInCatalog adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateCategoryInCatalog adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusCreated {
-		glg.Error("[SDK] Catalog CreateCategoryInCatalog status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog CreateCategoryInCatalog", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not create category in catalog '%s'",
-			merchantUUID, catalogID)
-		glg.Error("[SDK] Catalog CreateCategoryInCatalog err: ", err)
+			merchantUUID, catalogID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog CreateCategoryInCatalog err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Get Category success")
+	c.log.Info("[SDK] Get Category success")
 	return cr, json.Unmarshal(resp, &cr)
 }
 
 // GetCategoryInCatalog lists a category in a specified catalog
 func (c *catalogService) GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (cr CategoryResponse, err error) {
+	return c.GetCategoryInCatalogCtx(context.Background(), merchantUUID, catalogID, categoryID)
+}
+
+// GetCategoryInCatalogCtx works like GetCategoryInCatalog, honoring ctx cancellation and deadlines
+func (c *catalogService) GetCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, categoryID string) (cr CategoryResponse, err error) {
 	if err = verifyCategoryItems(merchantUUID, catalogID, categoryID); err != nil {
-		glg.Error("[SDK] Catalog GetCategoryInCatalog verifyCategoryItems: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog GetCategoryInCatalog auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog GetCategoryInCatalog verifyCategoryItems", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/catalogs/%s/categories/%s", merchantUUID, catalogID, categoryID)
-	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog GetCategoryInCatalog adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog GetCategoryInCatalog adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog GetCategoryInCatalog status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog GetCategoryInCatalog", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not get category '%s' in catalog '%s'",
-			merchantUUID, categoryID, catalogID)
-		glg.Error("[SDK] Catalog GetCategoryInCatalog err: ", err)
+			merchantUUID, categoryID, catalogID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog GetCategoryInCatalog err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Get Category success")
+	c.log.Info("[SDK] Get Category success")
 	return cr, json.Unmarshal(resp, &cr)
 }
 
@@ -121,18 +122,18 @@ func (c *catalogService) GetCategoryInCatalog(merchantUUID, catalogID, categoryI
 //
 // resource status = [AVAILABLE || UNAVAILABLE]
 func (c *catalogService) EditCategoryInCatalog(merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (cr CategoryCreateResponse, err error) {
+	return c.EditCategoryInCatalogCtx(context.Background(), merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode, sequence)
+}
+
+// EditCategoryInCatalogCtx works like EditCategoryInCatalog, honoring ctx cancellation and deadlines
+func (c *catalogService) EditCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (cr CategoryCreateResponse, err error) {
 	err = verifyNewCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, "DEFAULT")
 	if err != nil {
-		glg.Error("[SDK] Catalog EditCategoryInCatalog verifyNewCategoryInCatalog: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog EditCategoryInCatalog auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog EditCategoryInCatalog verifyNewCategoryInCatalog", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/catalogs/%s/categories/%s", merchantUUID, catalogID, categoryID)
@@ -144,55 +145,54 @@ func (c *catalogService) EditCategoryInCatalog(merchantUUID, catalogID, category
 	}
 	body, err := httpadapter.NewJsonReader(ci)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditCategoryInCatalog NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog EditCategoryInCatalog NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPatch, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPatch, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditCategoryInCatalog adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog EditCategoryInCatalog adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog EditCategoryInCatalog status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog EditCategoryInCatalog", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not edit category '%s' in catalog '%s'",
-			merchantUUID, catalogID, catalogID)
-		glg.Error("[SDK] Catalog EditCategoryInCatalog err: ", err)
+			merchantUUID, catalogID, catalogID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog EditCategoryInCatalog err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Edit Category success")
+	c.log.Info("[SDK] Edit Category success")
 	return cr, json.Unmarshal(resp, &cr)
 }
 
 // DeleteCategoryInCatalog removes a category in a specified catalog
 func (c *catalogService) DeleteCategoryInCatalog(merchantUUID, catalogID, categoryID string) (err error) {
+	return c.DeleteCategoryInCatalogCtx(context.Background(), merchantUUID, catalogID, categoryID)
+}
+
+// DeleteCategoryInCatalogCtx works like DeleteCategoryInCatalog, honoring ctx cancellation and deadlines
+func (c *catalogService) DeleteCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, categoryID string) (err error) {
 	if err = verifyCategoryItems(merchantUUID, catalogID, categoryID); err != nil {
-		glg.Error("[SDK] Catalog DeleteCategoryInCatalog verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteCategoryInCatalog verifyCategoryItems", logger.Err(err))
 		return
 	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog DeleteCategoryInCatalog auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/catalogs/%s/categories/%s", merchantUUID, catalogID, categoryID)
-	_, status, err := c.adapter.DoRequest(http.MethodDelete, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodDelete, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog DeleteCategoryInCatalog adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteCategoryInCatalog adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog DeleteCategoryInCatalog status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog DeleteCategoryInCatalog", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not delete category '%s' in catalog '%s'",
-			merchantUUID, catalogID, catalogID)
-		glg.Error("[SDK] Catalog DeleteCategoryInCatalog err: ", err)
+			merchantUUID, catalogID, catalogID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog DeleteCategoryInCatalog err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Delete product success")
+	c.log.Info("[SDK] Delete product success")
 	return
 }
 
@@ -201,7 +201,7 @@ func (c *CategoryItem) verify() (err error) {
 	if c.Price == empty {
 		return ErrNoPrice
 	}
-	if c.Price.Value == 0 {
+	if c.Price.Value.IsZero() {
 		return ErrNoItemPrice
 	}
 	if (c.Status != "AVAILABLE") && (c.Status != "UNAVAILABLE") {
@@ -1,25 +1,47 @@
 package catalog
 
+import "context"
+
 // Service describes the catalog abstraction
 type Service interface {
 	ListAllV2(merchantID string) (Catalogs, error)
+	ListAllV2Ctx(ctx context.Context, merchantID string) (Catalogs, error)
 	ListUnsellableItems(merchantUUID, catalogID string) (UnsellableResponse, error)
+	ListUnsellableItemsCtx(ctx context.Context, merchantUUID, catalogID string) (UnsellableResponse, error)
 	ListAllCategoriesInCatalog(merchantUUID, catalogID string) (CategoryResponse, error)
+	ListAllCategoriesInCatalogCtx(ctx context.Context, merchantUUID, catalogID string) (CategoryResponse, error)
 	CreateCategoryInCatalog(merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (CategoryCreateResponse, error)
+	CreateCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, name, resourceStatus, template, externalCode string) (CategoryCreateResponse, error)
 	GetCategoryInCatalog(merchantUUID, catalogID, categoryID string) (CategoryResponse, error)
+	GetCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, categoryID string) (CategoryResponse, error)
 	EditCategoryInCatalog(merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (CategoryCreateResponse, error)
+	EditCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, categoryID, name, resourceStatus, externalCode string, sequence int) (CategoryCreateResponse, error)
 	DeleteCategoryInCatalog(merchantUUID, catalogID, categoryID string) error
+	DeleteCategoryInCatalogCtx(ctx context.Context, merchantUUID, catalogID, categoryID string) error
 	ListProducts(merchantUUID string) (Products, error)
+	ListProductsCtx(ctx context.Context, merchantUUID string) (Products, error)
 	CreateProduct(merchantUUID string, product Product) (Product, error)
+	CreateProductCtx(ctx context.Context, merchantUUID string, product Product) (Product, error)
 	EditProduct(merchantUUID string, product Product) (Product, error)
+	EditProductCtx(ctx context.Context, merchantUUID string, product Product) (Product, error)
 	DeleteProduct(merchantUUID, productID string) error
+	DeleteProductCtx(ctx context.Context, merchantUUID, productID string) error
 	UpdateProductStatus(merchantUUID, productID, productStatus string) error
+	UpdateProductStatusCtx(ctx context.Context, merchantUUID, productID, productStatus string) error
 	LinkProductToCategory(merchantUUID, categoryID string, product ProductLink) error
+	LinkProductToCategoryCtx(ctx context.Context, merchantUUID, categoryID string, product ProductLink) error
 	CreatePizza(merchantUUID string, pizza Pizza) (Pizza, error)
+	CreatePizzaCtx(ctx context.Context, merchantUUID string, pizza Pizza) (Pizza, error)
 	ListPizzas(merchantUUID string) (Pizzas, error)
+	ListPizzasCtx(ctx context.Context, merchantUUID string) (Pizzas, error)
 	UpdatePizza(merchantUUID string, pizza Pizza) error
+	UpdatePizzaCtx(ctx context.Context, merchantUUID string, pizza Pizza) error
 	UpdatePizzaStatus(merchantUUID, pizzaStatus, pizzaID string) error
+	UpdatePizzaStatusCtx(ctx context.Context, merchantUUID, pizzaStatus, pizzaID string) error
 	UnlinkPizzaCategory(merchantUUID, pizzaID, categoryID string) error
+	UnlinkPizzaCategoryCtx(ctx context.Context, merchantUUID, pizzaID, categoryID string) error
 	LinkPizzaToCategory(merchantUUID, categoryID string, pizza Pizza) error
+	LinkPizzaToCategoryCtx(ctx context.Context, merchantUUID, categoryID string, pizza Pizza) error
 	UnlinkProductToCategory(merchantUUID, categoryID, productID string) error
+	UnlinkProductToCategoryCtx(ctx context.Context, merchantUUID, categoryID, productID string) error
 }
@@ -1,12 +1,15 @@
 package catalog
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
-	"github.com/kpango/glg"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 )
 
 // CreateItem product-category association
@@ -30,50 +33,44 @@ import (
 // 		"shifts":[{...}]
 // }
 func (c *catalogService) CreateItem(merchantID, categoryID, productID string, ci CategoryItem) (cp ProductLink, err error) {
+	return c.CreateItemCtx(context.Background(), merchantID, categoryID, productID, ci)
+}
+
+// CreateItemCtx works like CreateItem, honoring ctx cancellation and deadlines
+func (c *catalogService) CreateItemCtx(ctx context.Context, merchantID, categoryID, productID string, ci CategoryItem) (cp ProductLink, err error) {
 	err = verifyCategoryItems(merchantID, categoryID, productID)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateItem verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateItem verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if err = ci.verify(); err != nil {
-		glg.Error("[SDK] Catalog CreateItem verify: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog CreateItem auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateItem verify", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/categories/%s/products/%s", merchantID, categoryID, productID)
 	reader, err := httpadapter.NewJsonReader(ci)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateItem NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateItem NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, reader, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateItem adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateItem adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusCreated {
-		badResp := &apiError{}
-		err = json.Unmarshal(resp, badResp)
-		if err != nil {
-			glg.Error("[SDK] Catalog DeleteProduct Unmarshal: ", err)
-			return
-		}
-		glg.Error("[SDK] Catalog CreateItem status code: ", status, " merchant: ", merchantID)
-		err = fmt.Errorf(
-			"Merchant '%s' could not create item category '%s', code: '%s'",
-			merchantID, categoryID, badResp.Details.Code)
-		glg.Error("[SDK] Catalog CreateItem err: ", err)
+		c.log.Error("[SDK] Catalog CreateItem", logger.F("status", status), logger.F("merchantID", merchantID))
+		err = apierror.New(fmt.Sprintf(
+			"Merchant '%s' could not create item category '%s'",
+			merchantID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog CreateItem err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog CreateItem success product id '%s', merchant '%s'", productID, merchantID)
+	c.log.Info("[SDK] Catalog CreateItem success", logger.F("productID", productID), logger.F("merchantID", merchantID))
 	return cp, json.Unmarshal(resp, &cp)
 }
 
@@ -96,50 +93,44 @@ func (c *catalogService) CreateItem(merchantID, categoryID, productID string, ci
 // 		"shifts":[{...}]
 // }
 func (c *catalogService) EditItem(merchantID, categoryID, productID string, ci CategoryItem) (cp ProductLink, err error) {
+	return c.EditItemCtx(context.Background(), merchantID, categoryID, productID, ci)
+}
+
+// EditItemCtx works like EditItem, honoring ctx cancellation and deadlines
+func (c *catalogService) EditItemCtx(ctx context.Context, merchantID, categoryID, productID string, ci CategoryItem) (cp ProductLink, err error) {
 	err = verifyCategoryItems(merchantID, categoryID, productID)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditItem verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog EditItem verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if err = ci.verify(); err != nil {
-		glg.Error("[SDK] Catalog EditItem verify: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog EditItem auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog EditItem verify", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/categories/%s/products/%s", merchantID, categoryID, productID)
 	reader, err := httpadapter.NewJsonReader(ci)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditItem NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog EditItem NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPatch, endpoint, reader, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPatch, endpoint, reader, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditItem adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog EditItem adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		badResp := &apiError{}
-		err = json.Unmarshal(resp, badResp)
-		if err != nil {
-			glg.Error("[SDK] Catalog DeleteProduct Unmarshal: ", err)
-			return
-		}
-		glg.Error("[SDK] Catalog EditItem status code: ", status, " merchant: ", merchantID)
-		err = fmt.Errorf(
-			"Merchant '%s' could not create item category '%s', code: '%s'",
-			merchantID, categoryID, badResp.Details.Code)
-		glg.Error("[SDK] Catalog EditItem err: ", err)
+		c.log.Error("[SDK] Catalog EditItem", logger.F("status", status), logger.F("merchantID", merchantID))
+		err = apierror.New(fmt.Sprintf(
+			"Merchant '%s' could not create item category '%s'",
+			merchantID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog EditItem err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog EditItem success product id '%s', merchant '%s'", productID, merchantID)
+	c.log.Info("[SDK] Catalog EditItem success", logger.F("productID", productID), logger.F("merchantID", merchantID))
 	return cp, json.Unmarshal(resp, &cp)
 }
 
@@ -152,39 +143,32 @@ func (c *catalogService) EditItem(merchantID, categoryID, productID string, ci C
 // 404 not found
 //
 func (c *catalogService) DeleteItem(merchantID, categoryID, productID string) (err error) {
+	return c.DeleteItemCtx(context.Background(), merchantID, categoryID, productID)
+}
+
+// DeleteItemCtx works like DeleteItem, honoring ctx cancellation and deadlines
+func (c *catalogService) DeleteItemCtx(ctx context.Context, merchantID, categoryID, productID string) (err error) {
 	err = verifyCategoryItems(merchantID, categoryID, productID)
 	if err != nil {
-		glg.Error("[SDK] Catalog DeleteItem verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteItem verifyCategoryItems", logger.Err(err))
 		return
 	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog DeleteItem auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantID)
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/categories/%s/products/%s", merchantID, categoryID, productID)
-	resp, status, err := c.adapter.DoRequest(http.MethodDelete, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodDelete, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog DeleteItem adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteItem adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		badResp := &apiError{}
-		err = json.Unmarshal(resp, badResp)
-		if err != nil {
-			glg.Error("[SDK] Catalog DeleteProduct Unmarshal: ", err)
-			return
-		}
-		glg.Error("[SDK] Catalog DeleteItem status code: ", status, " merchant: ", merchantID)
-		err = fmt.Errorf(
-			"Merchant '%s' could not create item category '%s', code: '%s'",
-			merchantID, categoryID, badResp.Details.Code)
-		glg.Error("[SDK] Catalog DeleteItem err: ", err)
+		c.log.Error("[SDK] Catalog DeleteItem", logger.F("status", status), logger.F("merchantID", merchantID))
+		err = apierror.New(fmt.Sprintf(
+			"Merchant '%s' could not create item category '%s'",
+			merchantID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog DeleteItem err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog DeleteItem success product id '%s', merchant '%s'", productID, merchantID)
+	c.log.Info("[SDK] Catalog DeleteItem success", logger.F("productID", productID), logger.F("merchantID", merchantID))
 	return
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/money"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 	"github.com/stretchr/testify/assert"
 )
@@ -42,14 +43,14 @@ func TestCreateItem_OK(t *testing.T) {
 	ci := CategoryItem{
 		Name:   "id",
 		Status: "AVAILABLE",
-		Price:  Price{Value: 10},
+		Price:  Price{Value: money.FromCents(1000)},
 		Shifts: []Shift{
 			{StartTime: "00:00", EndTime: "23:59", Monday: true},
 		},
 	}
 	respCI, err := catalogService.CreateItem("merchant_id", "category_id", "product_id", ci)
 	assert.Nil(t, err)
-	assert.Equal(t, float64(10), respCI.Price.Value)
+	assert.True(t, money.FromCents(1000).Equal(respCI.Price.Value))
 	assert.Equal(t, 1, respCI.Sequence)
 }
 
@@ -84,14 +85,14 @@ func TestEditItem_OK(t *testing.T) {
 	ci := CategoryItem{
 		Name:   "id",
 		Status: "AVAILABLE",
-		Price:  Price{Value: 10},
+		Price:  Price{Value: money.FromCents(1000)},
 		Shifts: []Shift{
 			{StartTime: "00:00", EndTime: "23:59", Monday: true},
 		},
 	}
 	respCI, err := catalogService.EditItem("merchant_id", "category_id", "product_id", ci)
 	assert.Nil(t, err)
-	assert.Equal(t, float64(10), respCI.Price.Value)
+	assert.True(t, money.FromCents(1000).Equal(respCI.Price.Value))
 	assert.Equal(t, 1, respCI.Sequence)
 }
 
@@ -1,13 +1,16 @@
 package catalog
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
-	"github.com/kpango/glg"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 )
 
 type (
@@ -166,178 +169,175 @@ func (p *Pizza) verifyFields() (err error) {
 
 // ListProducts from a merchant
 func (c *catalogService) ListProducts(merchantUUID string) (ps Products, err error) {
+	return c.ListProductsCtx(context.Background(), merchantUUID)
+}
+
+// ListProductsCtx works like ListProducts, honoring ctx cancellation and deadlines
+func (c *catalogService) ListProductsCtx(ctx context.Context, merchantUUID string) (ps Products, err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog ListProducts verifyCategoryItems: ", err.Error())
-		return
-	}
-	err = c.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Catalog ListProducts auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog ListProducts verifyCategoryItems", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/products", merchantUUID)
-	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog ListProducts adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog ListProducts adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog ListProducts status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not get all products", merchantUUID)
-		glg.Error("[SDK] Catalog ListProducts err: ", err)
+		c.log.Error("[SDK] Catalog ListProducts", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not get all products", merchantUUID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog ListProducts err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] List products success, merchant '%s'", merchantUUID)
+	c.log.Info("[SDK] List products success", logger.F("merchantUUID", merchantUUID))
 	return ps, json.Unmarshal(resp, &ps)
 }
 
 // CreateProduct in a merchant
 func (c *catalogService) CreateProduct(merchantUUID string, product Product) (cp Product, err error) {
+	return c.CreateProductCtx(context.Background(), merchantUUID, product)
+}
+
+// CreateProductCtx works like CreateProduct, honoring ctx cancellation and deadlines
+func (c *catalogService) CreateProductCtx(ctx context.Context, merchantUUID string, product Product) (cp Product, err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog CreateProduct verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateProduct verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if err = product.verifyFields(); err != nil {
-		glg.Error("[SDK] Catalog CreateProduct verifyFields: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog CreateProduct auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateProduct verifyFields", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/products", merchantUUID)
 	body, err := httpadapter.NewJsonReader(product)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateProduct NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateProduct NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPost, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreateProduct adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog CreateProduct adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusCreated {
-		glg.Error("[SDK] Catalog CreateProduct status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not create product", merchantUUID)
-		glg.Error("[SDK] Catalog CreateProduct err: ", err)
+		c.log.Error("[SDK] Catalog CreateProduct", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not create product", merchantUUID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog CreateProduct err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Create product id '%s' success, merchant '%s'", cp.ID, merchantUUID)
+	c.log.Info("[SDK] Create product success", logger.F("productID", cp.ID), logger.F("merchantUUID", merchantUUID))
 	return cp, json.Unmarshal(resp, &cp)
 }
 
 // EditProduct in a merchant
 func (c *catalogService) EditProduct(merchantUUID string, product Product) (cp Product, err error) {
+	return c.EditProductCtx(context.Background(), merchantUUID, product)
+}
+
+// EditProductCtx works like EditProduct, honoring ctx cancellation and deadlines
+func (c *catalogService) EditProductCtx(ctx context.Context, merchantUUID string, product Product) (cp Product, err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog EditProduct verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog EditProduct verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if product.ID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog EditProduct err: ", err.Error())
+		c.log.Error("[SDK] Catalog EditProduct err", logger.Err(err))
 		return
 	}
 	if err = product.verifyFields(); err != nil {
-		glg.Error("[SDK] Catalog EditProduct verifyFields: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog EditProduct auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog EditProduct verifyFields", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/products/%s",
 		merchantUUID, product.ID)
 	body, err := httpadapter.NewJsonReader(product)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditProduct NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog EditProduct NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPut, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPut, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog EditProduct adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog EditProduct adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog EditProduct status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not edit product id '%s'", merchantUUID, product.ID)
-		glg.Error("[SDK] Catalog EditProduct err: ", err)
+		c.log.Error("[SDK] Catalog EditProduct", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not edit product id '%s'", merchantUUID, product.ID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog EditProduct err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog EditProduct id '%s' success, merchant '%s'", product.ID, merchantUUID)
+	c.log.Info("[SDK] Catalog EditProduct success", logger.F("productID", product.ID), logger.F("merchantUUID", merchantUUID))
 	return cp, json.Unmarshal(resp, &cp)
 }
 
 // DeleteProduct in a merchant
 func (c *catalogService) DeleteProduct(merchantUUID, productID string) (err error) {
+	return c.DeleteProductCtx(context.Background(), merchantUUID, productID)
+}
+
+// DeleteProductCtx works like DeleteProduct, honoring ctx cancellation and deadlines
+func (c *catalogService) DeleteProductCtx(ctx context.Context, merchantUUID, productID string) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog DeleteProduct verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteProduct verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if productID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog DeleteProduct err: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog DeleteProduct auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteProduct err", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/products/%s",
 		merchantUUID, productID)
-	resp, status, err := c.adapter.DoRequest(http.MethodDelete, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodDelete, endpoint, nil, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog DeleteProduct adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog DeleteProduct adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		badResp := &apiError{}
-		err = json.Unmarshal(resp, badResp)
-		if err != nil {
-			glg.Error("[SDK] Catalog DeleteProduct Unmarshal: ", err)
-			return
-		}
-		glg.Error("[SDK] Catalog DeleteProduct status code: ", status, " merchant: ", merchantUUID, " detail: ", badResp.Details.Code)
-		err = fmt.Errorf("Merchant '%s' could not delete product id '%s', code: '%s'", merchantUUID, productID, badResp.Details.Code)
-		glg.Error("[SDK] Catalog DeleteProduct err: ", err)
+		c.log.Error("[SDK] Catalog DeleteProduct", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not delete product id '%s'", merchantUUID, productID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog DeleteProduct err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog DeleteProduct id '%s' success, merchant '%s'", productID, merchantUUID)
+	c.log.Info("[SDK] Catalog DeleteProduct success", logger.F("productID", productID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
 // UpdateProductStatus in a merchant
 func (c *catalogService) UpdateProductStatus(merchantUUID, productID, productStatus string) (err error) {
+	return c.UpdateProductStatusCtx(context.Background(), merchantUUID, productID, productStatus)
+}
+
+// UpdateProductStatusCtx works like UpdateProductStatus, honoring ctx cancellation and deadlines
+func (c *catalogService) UpdateProductStatusCtx(ctx context.Context, merchantUUID, productID, productStatus string) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog UpdateProductStatus verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdateProductStatus verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if productID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog UpdateProductStatus err: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdateProductStatus err", logger.Err(err))
 		return
 	}
 	if (productStatus != "AVAILABLE") && (productStatus != "UNAVAILABLE") {
 		err = fmt.Errorf("product status '%s' should be 'AVAILABLE' or 'UNAVAILABLE'", productStatus)
-		glg.Error("[SDK] Catalog UpdateProductStatus err: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog UpdateProductStatus auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdateProductStatus err", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/products/%s/status",
 		merchantUUID, productID)
@@ -346,210 +346,214 @@ func (c *catalogService) UpdateProductStatus(merchantUUID, productID, productSta
 	}{Status: productStatus}
 	body, err := httpadapter.NewJsonReader(bodyStatus)
 	if err != nil {
-		glg.Error("[SDK] Catalog UpdateProductStatus NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdateProductStatus NewJsonReader error", logger.Err(err))
 		return
 	}
-	_, status, err := c.adapter.DoRequest(http.MethodPatch, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPatch, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog UpdateProductStatus adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdateProductStatus adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog UpdateProductStatus status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not update product id '%s'", merchantUUID, productID)
-		glg.Error("[SDK] Catalog UpdateProductStatus err: ", err)
+		c.log.Error("[SDK] Catalog UpdateProductStatus", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not update product id '%s'", merchantUUID, productID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog UpdateProductStatus err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog UpdateProductStatus id '%s' success, merchant '%s'", productID, merchantUUID)
+	c.log.Info("[SDK] Catalog UpdateProductStatus success", logger.F("productID", productID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
 // LinkProductToCategory in a merchant
 func (c *catalogService) LinkProductToCategory(merchantUUID, categoryID string, product ProductLink) (err error) {
+	return c.LinkProductToCategoryCtx(context.Background(), merchantUUID, categoryID, product)
+}
+
+// LinkProductToCategoryCtx works like LinkProductToCategory, honoring ctx cancellation and deadlines
+func (c *catalogService) LinkProductToCategoryCtx(ctx context.Context, merchantUUID, categoryID string, product ProductLink) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", categoryID); err != nil {
-		glg.Error("[SDK] Catalog LinkProductToCategory verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkProductToCategory verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if product.ID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog LinkProductToCategory err: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkProductToCategory err", logger.Err(err))
 		return
 	}
 	if (product.Status != "AVAILABLE") && (product.Status != "UNAVAILABLE") {
 		err = fmt.Errorf("product status '%s' should be 'AVAILABLE' or 'UNAVAILABLE'", product.Status)
-		glg.Error("[SDK] Catalog LinkProductToCategory err: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog LinkProductToCategory auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkProductToCategory err", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/categories/%s/products/%s",
 		merchantUUID, categoryID, product.ID)
 	body, err := httpadapter.NewJsonReader(product)
 	if err != nil {
-		glg.Error("[SDK] Catalog LinkProductToCategory NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkProductToCategory NewJsonReader error", logger.Err(err))
 		return
 	}
-	_, status, err := c.adapter.DoRequest(http.MethodPatch, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPatch, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog LinkProductToCategory adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkProductToCategory adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusCreated {
-		glg.Error("[SDK] Catalog LinkProductToCategory status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog LinkProductToCategory", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not link product id '%s' to category '%s'",
-			merchantUUID, product.ID, categoryID)
-		glg.Error("[SDK] Catalog LinkProductToCategory err: ", err)
+			merchantUUID, product.ID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog LinkProductToCategory err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog LinkProductToCategory id '%s' success, merchant '%s'", product.ID, merchantUUID)
+	c.log.Info("[SDK] Catalog LinkProductToCategory success", logger.F("productID", product.ID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
 // UnlinkProductToCategory in a merchant
 func (c *catalogService) UnlinkProductToCategory(merchantUUID, categoryID, productID string) (err error) {
+	return c.UnlinkProductToCategoryCtx(context.Background(), merchantUUID, categoryID, productID)
+}
+
+// UnlinkProductToCategoryCtx works like UnlinkProductToCategory, honoring ctx cancellation and deadlines
+func (c *catalogService) UnlinkProductToCategoryCtx(ctx context.Context, merchantUUID, categoryID, productID string) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", categoryID); err != nil {
-		glg.Error("[SDK] Catalog UnlinkProductToCategory verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog UnlinkProductToCategory verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if productID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog UnlinkProductToCategory err: ", err.Error())
+		c.log.Error("[SDK] Catalog UnlinkProductToCategory err", logger.Err(err))
 		return
 	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog UnlinkProductToCategory auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/categories/%s/products/%s",
 		merchantUUID, categoryID, productID)
-	_, status, err := c.adapter.DoRequest(http.MethodDelete, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodDelete, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog UnlinkProductToCategory adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog UnlinkProductToCategory adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog UnlinkProductToCategory status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog UnlinkProductToCategory", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could not unlink product id '%s' to category '%s'",
-			merchantUUID, productID, categoryID)
-		glg.Error("[SDK] Catalog UnlinkProductToCategory err: ", err)
+			merchantUUID, productID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog UnlinkProductToCategory err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Catalog UnlinkProductToCategory id '%s' success, merchant '%s'", productID, merchantUUID)
+	c.log.Info("[SDK] Catalog UnlinkProductToCategory success", logger.F("productID", productID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
 // CreatePizza in a merchant
 func (c *catalogService) CreatePizza(merchantUUID string, pizza Pizza) (cp Pizza, err error) {
+	return c.CreatePizzaCtx(context.Background(), merchantUUID, pizza)
+}
+
+// CreatePizzaCtx works like CreatePizza, honoring ctx cancellation and deadlines
+func (c *catalogService) CreatePizzaCtx(ctx context.Context, merchantUUID string, pizza Pizza) (cp Pizza, err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog CreatePizza verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog CreatePizza verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if err = pizza.verifyFields(); err != nil {
-		glg.Error("[SDK] Catalog CreatePizza verifyFields: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog CreatePizza auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog CreatePizza verifyFields", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/pizzas", merchantUUID)
 	body, err := httpadapter.NewJsonReader(pizza)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreatePizza NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog CreatePizza NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := c.adapter.DoRequest(http.MethodPost, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog CreatePizza adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog CreatePizza adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusCreated {
-		glg.Error("[SDK] Catalog CreatePizza status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not create pizza", merchantUUID)
-		glg.Error("[SDK] Catalog CreatePizza err: ", err)
+		c.log.Error("[SDK] Catalog CreatePizza", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not create pizza", merchantUUID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog CreatePizza err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Create pizza id '%s' success, merchant '%s'", cp.ID, merchantUUID)
+	c.log.Info("[SDK] Create pizza success", logger.F("pizzaID", cp.ID), logger.F("merchantUUID", merchantUUID))
 	return cp, json.Unmarshal(resp, &cp)
 }
 
 // ListPizzas in a merchant
 func (c *catalogService) ListPizzas(merchantUUID string) (pz Pizzas, err error) {
+	return c.ListPizzasCtx(context.Background(), merchantUUID)
+}
+
+// ListPizzasCtx works like ListPizzas, honoring ctx cancellation and deadlines
+func (c *catalogService) ListPizzasCtx(ctx context.Context, merchantUUID string) (pz Pizzas, err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog ListPizzas verifyCategoryItems: ", err.Error())
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog ListPizzas auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog ListPizzas verifyCategoryItems", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/pizzas", merchantUUID)
-	resp, status, err := c.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog ListPizzas adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog ListPizzas adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Catalog ListPizzas status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not list pizzas", merchantUUID)
-		glg.Error("[SDK] Catalog ListPizzas err: ", err)
+		c.log.Error("[SDK] Catalog ListPizzas", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not list pizzas", merchantUUID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog ListPizzas err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] List pizzas merchant '%s' success", merchantUUID)
+	c.log.Info("[SDK] List pizzas success", logger.F("merchantUUID", merchantUUID))
 	return pz, json.Unmarshal(resp, &pz)
 }
 
 // UpdatePizza in a merchant
 func (c *catalogService) UpdatePizza(merchantUUID string, pizza Pizza) (err error) {
+	return c.UpdatePizzaCtx(context.Background(), merchantUUID, pizza)
+}
+
+// UpdatePizzaCtx works like UpdatePizza, honoring ctx cancellation and deadlines
+func (c *catalogService) UpdatePizzaCtx(ctx context.Context, merchantUUID string, pizza Pizza) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog UpdatePizza verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizza verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if pizza.ID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog UpdatePizza verifyFields: ", err.Error(), " merchant ", merchantUUID)
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog UpdatePizza auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizza verifyFields", logger.Err(err), logger.F("merchantUUID", merchantUUID))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/pizzas/%s", merchantUUID, pizza.ID)
 	body, err := httpadapter.NewJsonReader(pizza)
 	if err != nil {
-		glg.Error("[SDK] Catalog UpdatePizza NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizza NewJsonReader error", logger.Err(err))
 		return
 	}
-	_, status, err := c.adapter.DoRequest(http.MethodPut, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPut, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog UpdatePizza adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizza adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog UpdatePizza status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not create pizza", merchantUUID)
-		glg.Error("[SDK] Catalog UpdatePizza err: ", err)
+		c.log.Error("[SDK] Catalog UpdatePizza", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not create pizza", merchantUUID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog UpdatePizza err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Update pizza id '%s' success, merchant '%s'", pizza.ID, merchantUUID)
+	c.log.Info("[SDK] Update pizza success", logger.F("pizzaID", pizza.ID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
@@ -557,26 +561,27 @@ func (c *catalogService) UpdatePizza(merchantUUID string, pizza Pizza) (err erro
 //
 // pizzaStatus = [AVAILABLE || UNAVAILABLE]
 func (c *catalogService) UpdatePizzaStatus(merchantUUID, pizzaStatus, pizzaID string) (err error) {
+	return c.UpdatePizzaStatusCtx(context.Background(), merchantUUID, pizzaStatus, pizzaID)
+}
+
+// UpdatePizzaStatusCtx works like UpdatePizzaStatus, honoring ctx cancellation and deadlines
+func (c *catalogService) UpdatePizzaStatusCtx(ctx context.Context, merchantUUID, pizzaStatus, pizzaID string) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", "categoryID"); err != nil {
-		glg.Error("[SDK] Catalog UpdatePizzaStatus verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if pizzaID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog UpdatePizzaStatus verifyFields: ", err.Error(), " merchant ", merchantUUID)
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus verifyFields", logger.Err(err), logger.F("merchantUUID", merchantUUID))
 		return
 	}
 	if (pizzaStatus != "AVAILABLE") && (pizzaStatus != "UNAVAILABLE") {
 		err = fmt.Errorf("pizza status '%s' should be 'AVAILABLE' or 'UNAVAILABLE'", pizzaStatus)
-		glg.Error("[SDK] Catalog UpdatePizzaStatus verifyFields: ", err.Error(), " merchant ", merchantUUID)
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog UpdatePizzaStatus auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus verifyFields", logger.Err(err), logger.F("merchantUUID", merchantUUID))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf("/merchants/%s/pizzas/%s", merchantUUID, pizzaID)
 	updateBody := struct {
@@ -584,97 +589,98 @@ func (c *catalogService) UpdatePizzaStatus(merchantUUID, pizzaStatus, pizzaID st
 	}{pizzaStatus}
 	body, err := httpadapter.NewJsonReader(updateBody)
 	if err != nil {
-		glg.Error("[SDK] Catalog UpdatePizzaStatus NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus NewJsonReader error", logger.Err(err))
 		return
 	}
-	_, status, err := c.adapter.DoRequest(http.MethodPatch, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPatch, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog UpdatePizzaStatus adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog UpdatePizzaStatus status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not update pizza id '%s' status", merchantUUID, pizzaID)
-		glg.Error("[SDK] Catalog UpdatePizzaStatus err: ", err)
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not update pizza id '%s' status", merchantUUID, pizzaID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog UpdatePizzaStatus err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Update pizza id '%s' success, merchant '%s'", pizzaID, merchantUUID)
+	c.log.Info("[SDK] Update pizza success", logger.F("pizzaID", pizzaID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
 // LinkPizzaToCategory in a merchant
 func (c *catalogService) LinkPizzaToCategory(merchantUUID, categoryID string, pizza Pizza) (err error) {
+	return c.LinkPizzaToCategoryCtx(context.Background(), merchantUUID, categoryID, pizza)
+}
+
+// LinkPizzaToCategoryCtx works like LinkPizzaToCategory, honoring ctx cancellation and deadlines
+func (c *catalogService) LinkPizzaToCategoryCtx(ctx context.Context, merchantUUID, categoryID string, pizza Pizza) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", categoryID); err != nil {
-		glg.Error("[SDK] Catalog LinkPizzaToCategory verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkPizzaToCategory verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if pizza.ID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog LinkPizzaToCategory verifyFields: ", err.Error(), " merchant ", merchantUUID)
-		return
-	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog LinkPizzaToCategory auth.Validate: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkPizzaToCategory verifyFields", logger.Err(err), logger.F("merchantUUID", merchantUUID))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
 	headers["Content-Type"] = "application/json"
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/pizzas/%s/categories/%s", merchantUUID, pizza.ID, categoryID)
 	body, err := httpadapter.NewJsonReader(pizza)
 	if err != nil {
-		glg.Error("[SDK] Catalog LinkPizzaToCategory NewJsonReader error: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkPizzaToCategory NewJsonReader error", logger.Err(err))
 		return
 	}
-	_, status, err := c.adapter.DoRequest(http.MethodPost, endpoint, body, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] Catalog LinkPizzaToCategory adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog LinkPizzaToCategory adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog LinkPizzaToCategory status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not link pizza id '%s' to category '%s'",
-			merchantUUID, pizza.ID, categoryID)
-		glg.Error("[SDK] Catalog LinkPizzaToCategory err: ", err)
+		c.log.Error("[SDK] Catalog LinkPizzaToCategory", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not link pizza id '%s' to category '%s'",
+			merchantUUID, pizza.ID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog LinkPizzaToCategory err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Update pizza id '%s' success, merchant '%s'", pizza.ID, merchantUUID)
+	c.log.Info("[SDK] Update pizza success", logger.F("pizzaID", pizza.ID), logger.F("merchantUUID", merchantUUID))
 	return
 }
 
 // UnlinkPizzaCategory in a merchant category
 func (c *catalogService) UnlinkPizzaCategory(merchantUUID, pizzaID, categoryID string) (err error) {
+	return c.UnlinkPizzaCategoryCtx(context.Background(), merchantUUID, pizzaID, categoryID)
+}
+
+// UnlinkPizzaCategoryCtx works like UnlinkPizzaCategory, honoring ctx cancellation and deadlines
+func (c *catalogService) UnlinkPizzaCategoryCtx(ctx context.Context, merchantUUID, pizzaID, categoryID string) (err error) {
 	if err = verifyCategoryItems(merchantUUID, "catalogID", categoryID); err != nil {
-		glg.Error("[SDK] Catalog UnlinkPizzaCategory verifyCategoryItems: ", err.Error())
+		c.log.Error("[SDK] Catalog UnlinkPizzaCategory verifyCategoryItems", logger.Err(err))
 		return
 	}
 	if pizzaID == "" {
 		err = ErrNoProductID
-		glg.Error("[SDK] Catalog UnlinkPizzaCategory verifyFields: ", err.Error(), " merchant ", merchantUUID)
+		c.log.Error("[SDK] Catalog UnlinkPizzaCategory verifyFields", logger.Err(err), logger.F("merchantUUID", merchantUUID))
 		return
 	}
-	if err = c.auth.Validate(); err != nil {
-		glg.Error("[SDK] Catalog UnlinkPizzaCategory auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", c.auth.GetToken())
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	endpoint := v2Endpoint + fmt.Sprintf(
 		"/merchants/%s/pizzas/%s/categories/%s", merchantUUID, pizzaID, categoryID)
-	_, status, err := c.adapter.DoRequest(http.MethodDelete, endpoint, nil, headers)
+	resp, status, err := c.adapter.DoRequestCtx(ctx, http.MethodDelete, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Catalog UnlinkPizzaCategory adapter.DoRequest: ", err.Error())
+		c.log.Error("[SDK] Catalog UnlinkPizzaCategory adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status >= http.StatusBadRequest {
-		glg.Error("[SDK] Catalog UnlinkPizzaCategory status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf(
+		c.log.Error("[SDK] Catalog UnlinkPizzaCategory", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf(
 			"Merchant '%s' could unlink pizza id '%s' from category '%s'",
-			merchantUUID, pizzaID, categoryID)
-		glg.Error("[SDK] Catalog UnlinkPizzaCategory err: ", err)
+			merchantUUID, pizzaID, categoryID), status, endpoint, resp)
+		c.log.Error("[SDK] Catalog UnlinkPizzaCategory err", logger.Err(err))
 		return
 	}
-	glg.Infof("[SDK] Update pizza id '%s' success, merchant '%s'", pizzaID, merchantUUID)
+	c.log.Info("[SDK] Update pizza success", logger.F("pizzaID", pizzaID), logger.F("merchantUUID", merchantUUID))
 	return
 }
@@ -1,5 +1,7 @@
 package catalog
 
+import "github.com/arxdsilva/golang-ifood-sdk/money"
+
 type (
 	// Catalogs group of Catalog
 	Catalogs []Catalog
@@ -111,8 +113,8 @@ type (
 
 	// Price of a product object
 	Price struct {
-		Value         float64 `json:"value"`
-		OriginalValue float64 `json:"originalValue"`
+		Value         money.Money `json:"value"`
+		OriginalValue money.Money `json:"originalValue"`
 	}
 
 	// Shift of a Item or CategoryItem
@@ -127,10 +129,4 @@ type (
 		Saturday  bool   `json:"saturday"`
 		Sunday    bool   `json:"sunday"`
 	}
-
-	apiError struct {
-		Details struct {
-			Code string `json:"code"`
-		} `json:"details"`
-	}
 )
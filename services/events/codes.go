@@ -0,0 +1,209 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// EventCode is the code of a V2 event, codes the SDK does not
+// know yet are kept as sent by iFood
+type EventCode string
+
+// V2 event codes of the order lifecycle
+const (
+	EventPlaced                        EventCode = "PLC"
+	EventConfirmed                     EventCode = "CFM"
+	EventPreparationStarted            EventCode = "PRS"
+	EventSeparationStarted             EventCode = "SPS"
+	EventSeparationEnded               EventCode = "SPE"
+	EventReadyToPickup                 EventCode = "RTP"
+	EventDispatched                    EventCode = "DSP"
+	EventConcluded                     EventCode = "CON"
+	EventCancelled                     EventCode = "CAN"
+	EventCancellationRequested         EventCode = "CAR"
+	EventCancellationRequestFailed     EventCode = "CARF"
+	EventConsumerCancellationRequested EventCode = "CCR"
+	EventConsumerCancellationAccepted  EventCode = "CCA"
+	EventConsumerCancellationDenied    EventCode = "CCD"
+	EventHandshakeDispute              EventCode = "HSD"
+	EventHandshakeSettlement           EventCode = "HSS"
+	EventAssignDriver                  EventCode = "ADR"
+	EventGoingToOrigin                 EventCode = "GTO"
+	EventArrivedAtOrigin               EventCode = "AAO"
+	EventCollected                     EventCode = "CLT"
+	EventArrivedAtDestination          EventCode = "AAD"
+)
+
+// ErrMetadataMismatch the event code has no metadata of the requested kind
+var ErrMetadataMismatch = errors.New("event code has no such metadata")
+
+// fullCodes are the V2 full codes by code
+var fullCodes = map[EventCode]string{
+	EventPlaced:                        "PLACED",
+	EventConfirmed:                     "CONFIRMED",
+	EventPreparationStarted:            "PREPARATION_STARTED",
+	EventSeparationStarted:             "SEPARATION_STARTED",
+	EventSeparationEnded:               "SEPARATION_ENDED",
+	EventReadyToPickup:                 "READY_TO_PICKUP",
+	EventDispatched:                    "DISPATCHED",
+	EventConcluded:                     "CONCLUDED",
+	EventCancelled:                     "CANCELLED",
+	EventCancellationRequested:         "CANCELLATION_REQUESTED",
+	EventCancellationRequestFailed:     "CANCELLATION_REQUEST_FAILED",
+	EventConsumerCancellationRequested: "CONSUMER_CANCELLATION_REQUESTED",
+	EventConsumerCancellationAccepted:  "CONSUMER_CANCELLATION_ACCEPTED",
+	EventConsumerCancellationDenied:    "CONSUMER_CANCELLATION_DENIED",
+	EventHandshakeDispute:              "HANDSHAKE_DISPUTE",
+	EventHandshakeSettlement:           "HANDSHAKE_SETTLEMENT",
+	EventAssignDriver:                  "ASSIGN_DRIVER",
+	EventGoingToOrigin:                 "GOING_TO_ORIGIN",
+	EventArrivedAtOrigin:               "ARRIVED_AT_ORIGIN",
+	EventCollected:                     "COLLECTED",
+	EventArrivedAtDestination:          "ARRIVED_AT_DESTINATION",
+}
+
+type (
+	// CancellationMetadata is the metadata of CAN and CANCELLED events
+	CancellationMetadata struct {
+		Stage      string                 `json:"CANCEL_STAGE"`
+		Code       string                 `json:"CANCEL_CODE"`
+		Origin     string                 `json:"CANCEL_ORIGIN"`
+		Reason     string                 `json:"CANCEL_REASON"`
+		Occurrence CancellationOccurrence `json:"CANCELLATION_OCCURRENCE"`
+	}
+
+	// CancellationOccurrence tells who bears the cancellation costs
+	CancellationOccurrence struct {
+		Restaurant Occurrence `json:"RESTAURANT"`
+		Consumer   Occurrence `json:"CONSUMER"`
+		Logistic   Occurrence `json:"LOGISTIC"`
+	}
+
+	// Occurrence is the financial occurrence of a cancellation party
+	Occurrence struct {
+		FinancialOccurrence string `json:"FINANCIAL_OCCURRENCE"`
+		PaymentType         string `json:"PAYMENT_TYPE"`
+	}
+
+	// CancellationRequestMetadata is the metadata of CAR and CCR events,
+	// the cancellation the merchant or the customer asked for
+	CancellationRequestMetadata struct {
+		Stage  string `json:"CANCEL_STAGE"`
+		Code   string `json:"CANCEL_CODE"`
+		Origin string `json:"CANCEL_ORIGIN"`
+		Reason string `json:"CANCEL_REASON"`
+	}
+
+	// DriverMetadata is the metadata of ADR events, the driver iFood
+	// assigned to the order
+	DriverMetadata struct {
+		Name        string `json:"workerName"`
+		Phone       string `json:"workerPhone"`
+		PhotoURL    string `json:"workerPhotoUrl"`
+		VehicleType string `json:"workerVehicleType"`
+		ExternalID  string `json:"workerExternalUuid"`
+	}
+
+	// HandshakeDisputeMetadata is the metadata of HSD events, the
+	// merchant answers the dispute before it expires
+	HandshakeDisputeMetadata struct {
+		DisputeID     string                   `json:"disputeId"`
+		Action        string                   `json:"action"`
+		Message       string                   `json:"message"`
+		HandshakeType string                   `json:"handshakeType"`
+		TimeoutAction string                   `json:"timeoutAction"`
+		ExpiresAt     time.Time                `json:"expiresAt"`
+		Alternatives  []map[string]interface{} `json:"alternatives"`
+	}
+)
+
+// ParseEventCode returns the code of a code or a V2 full code,
+// unknown values are returned as they are
+func ParseEventCode(s string) EventCode {
+	for code, full := range fullCodes {
+		if full == s {
+			return code
+		}
+	}
+	return EventCode(s)
+}
+
+// FullCode returns the V2 full code, empty when the code is unknown
+func (c EventCode) FullCode() string {
+	return fullCodes[c]
+}
+
+// Known tells whether the SDK knows the code
+func (c EventCode) Known() bool {
+	_, ok := fullCodes[c]
+	return ok
+}
+
+// DecodeMetadata decodes the metadata into v
+func (e V2Event) DecodeMetadata(v interface{}) error {
+	return decodeMetadata(e.Metadata, v)
+}
+
+// CancellationMetadata decodes the metadata of a CAN event
+func (e V2Event) CancellationMetadata() (m *CancellationMetadata, err error) {
+	if e.Code != EventCancelled {
+		return nil, ErrMetadataMismatch
+	}
+	m = new(CancellationMetadata)
+	err = e.DecodeMetadata(m)
+	return
+}
+
+// CancellationRequestMetadata decodes the metadata of a CAR or CCR event
+func (e V2Event) CancellationRequestMetadata() (m *CancellationRequestMetadata, err error) {
+	if e.Code != EventCancellationRequested && e.Code != EventConsumerCancellationRequested {
+		return nil, ErrMetadataMismatch
+	}
+	m = new(CancellationRequestMetadata)
+	err = e.DecodeMetadata(m)
+	return
+}
+
+// DriverMetadata decodes the metadata of an ADR event
+func (e V2Event) DriverMetadata() (m *DriverMetadata, err error) {
+	if e.Code != EventAssignDriver {
+		return nil, ErrMetadataMismatch
+	}
+	m = new(DriverMetadata)
+	err = e.DecodeMetadata(m)
+	return
+}
+
+// HandshakeDisputeMetadata decodes the metadata of a HSD event
+func (e V2Event) HandshakeDisputeMetadata() (m *HandshakeDisputeMetadata, err error) {
+	if e.Code != EventHandshakeDispute {
+		return nil, ErrMetadataMismatch
+	}
+	m = new(HandshakeDisputeMetadata)
+	err = e.DecodeMetadata(m)
+	return
+}
+
+// DecodeMetadata decodes the metadata into v
+func (e Event) DecodeMetadata(v interface{}) error {
+	return decodeMetadata(e.Metadata, v)
+}
+
+// CancellationMetadata decodes the metadata of a CANCELLED event
+func (e Event) CancellationMetadata() (m *CancellationMetadata, err error) {
+	if e.Code != ValidEventsByCodeName["CAN"] {
+		return nil, ErrMetadataMismatch
+	}
+	m = new(CancellationMetadata)
+	err = e.DecodeMetadata(m)
+	return
+}
+
+func decodeMetadata(metadata map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
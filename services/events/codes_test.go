@@ -0,0 +1,91 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventCode(t *testing.T) {
+	assert.Equal(t, EventPlaced, ParseEventCode("PLACED"))
+	assert.Equal(t, EventReadyToPickup, ParseEventCode("RTP"))
+	assert.Equal(t, "HANDSHAKE_DISPUTE", EventHandshakeDispute.FullCode())
+	assert.Equal(t, EventPreparationStarted, ParseEventCode("PREPARATION_STARTED"))
+	assert.Equal(t, "PREPARATION_STARTED", EventPreparationStarted.FullCode())
+	unknown := ParseEventCode("NEW_CODE")
+	assert.Equal(t, EventCode("NEW_CODE"), unknown)
+	assert.False(t, unknown.Known())
+	assert.True(t, EventConcluded.Known())
+}
+
+func TestV2Event_UnknownCodeKept(t *testing.T) {
+	var e V2Event
+	require.Nil(t, json.Unmarshal([]byte(`{"code":"XYZ","fullCode":"SOMETHING_NEW","metadata":{"a":1}}`), &e))
+	assert.Equal(t, EventCode("XYZ"), e.Code)
+	out, err := json.Marshal(e)
+	require.Nil(t, err)
+	assert.Contains(t, string(out), `"code":"XYZ"`)
+	_, err = e.CancellationMetadata()
+	assert.Equal(t, ErrMetadataMismatch, err)
+	var raw map[string]int
+	assert.Nil(t, e.DecodeMetadata(&raw))
+	assert.Equal(t, 1, raw["a"])
+}
+
+func TestEvent_CancellationMetadata(t *testing.T) {
+	var polled []Event
+	require.Nil(t, json.Unmarshal([]byte(pollAPIResponse), &polled))
+	_, err := polled[0].CancellationMetadata()
+	assert.Equal(t, ErrMetadataMismatch, err)
+	m, err := polled[1].CancellationMetadata()
+	require.Nil(t, err)
+	assert.Equal(t, "[PRE_CONFIRMED]", m.Stage)
+	assert.Equal(t, "902", m.Code)
+	assert.Equal(t, "NA", m.Occurrence.Restaurant.FinancialOccurrence)
+	assert.Equal(t, "NA", m.Occurrence.Logistic.PaymentType)
+}
+
+func TestV2Event_HandshakeDisputeMetadata(t *testing.T) {
+	var e V2Event
+	body := `{"code":"HSD","fullCode":"HANDSHAKE_DISPUTE","metadata":{"disputeId":"d1","action":"CANCELLATION","handshakeType":"AFTER_DELIVERY","timeoutAction":"ACCEPT_CANCELLATION","expiresAt":"2023-01-02T15:04:05Z"}}`
+	require.Nil(t, json.Unmarshal([]byte(body), &e))
+	m, err := e.HandshakeDisputeMetadata()
+	require.Nil(t, err)
+	assert.Equal(t, "d1", m.DisputeID)
+	assert.Equal(t, "ACCEPT_CANCELLATION", m.TimeoutAction)
+	assert.Equal(t, 2023, m.ExpiresAt.Year())
+	e.Metadata = nil
+	m, err = e.HandshakeDisputeMetadata()
+	assert.Nil(t, err)
+	assert.NotNil(t, m)
+}
+
+func TestV2Event_CancellationRequestMetadata(t *testing.T) {
+	for _, body := range []string{
+		`{"code":"CAR","metadata":{"CANCEL_CODE":"501","CANCEL_ORIGIN":"RESTAURANT","CANCEL_REASON":"Problemas de sistema"}}`,
+		`{"code":"CCR","metadata":{"CANCEL_CODE":"501","CANCEL_ORIGIN":"CONSUMER","CANCEL_REASON":"Problemas de sistema"}}`,
+	} {
+		var e V2Event
+		require.Nil(t, json.Unmarshal([]byte(body), &e))
+		m, err := e.CancellationRequestMetadata()
+		require.Nil(t, err, body)
+		assert.Equal(t, "501", m.Code)
+		assert.Equal(t, "Problemas de sistema", m.Reason)
+	}
+	_, err := V2Event{Code: EventCancelled}.CancellationRequestMetadata()
+	assert.Equal(t, ErrMetadataMismatch, err)
+}
+
+func TestV2Event_DriverMetadata(t *testing.T) {
+	var e V2Event
+	body := `{"code":"ADR","fullCode":"ASSIGN_DRIVER","metadata":{"workerName":"Maria","workerPhone":"11999999999","workerVehicleType":"MOTORCYCLE"}}`
+	require.Nil(t, json.Unmarshal([]byte(body), &e))
+	m, err := e.DriverMetadata()
+	require.Nil(t, err)
+	assert.Equal(t, "Maria", m.Name)
+	assert.Equal(t, "MOTORCYCLE", m.VehicleType)
+	_, err = V2Event{Code: EventPlaced}.DriverMetadata()
+	assert.Equal(t, ErrMetadataMismatch, err)
+}
@@ -0,0 +1,342 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+const (
+	// DefaultPollInterval is the polling cadence recommended by iFood
+	DefaultPollInterval = time.Second * 30
+	// DefaultMaxBackoff caps the wait after consecutive rate limited polls
+	DefaultMaxBackoff = time.Minute * 5
+	// DefaultSeenTTL is how long the Seen store remembers an event
+	DefaultSeenTTL = time.Hour * 24
+	// DefaultPruneInterval is the cadence the Seen store is pruned at
+	DefaultPruneInterval = time.Hour
+	// ackTimeout bounds the acknowledgment of a batch handled before ctx was cancelled
+	ackTimeout = time.Second * 10
+)
+
+// ErrNoHandler no handler is registered for the event code or full code
+var ErrNoHandler = errors.New("no handler registered for the event")
+
+// ErrHandlerPanic a handler panicked, the event is handled as failed
+var ErrHandlerPanic = errors.New("event handler panicked")
+
+type (
+	// Handler handles a V2 event, an error leaves the event
+	// unacknowledged so it is polled again
+	Handler interface {
+		Handle(ctx context.Context, e V2Event) error
+	}
+
+	// HandlerFunc adapts a function to Handler
+	HandlerFunc func(ctx context.Context, e V2Event) error
+
+	// Registry dispatches events to the handlers registered by
+	// Code (PLC, CFM, CAN, ...) or Fullcode (PLACED, CONFIRMED, ...),
+	// it is safe for concurrent use
+	Registry struct {
+		mu       sync.RWMutex
+		handlers map[EventCode][]Handler
+		fallback Handler
+	}
+
+	// Consumer polls the V2 events, dispatches them to the Registry and
+	// acknowledges the ones handled successfully, in a single call per poll
+	Consumer struct {
+		Events   Service
+		Registry *Registry
+		// Merchants filters the polled merchants, in batches of MaxPollingMerchants
+		Merchants []string
+		// Interval between polls, DefaultPollInterval when zero
+		Interval time.Duration
+		// MaxBackoff caps the wait after rate limited calls, DefaultMaxBackoff when zero
+		MaxBackoff time.Duration
+		// AckUnhandled acknowledges the events no handler is registered for
+		AckUnhandled bool
+		// MaxAttempts is how many polls an event is handled before it is
+		// put in DeadLetters and acknowledged, zero retries it forever
+		MaxAttempts int
+		// AttemptsByCode overrides MaxAttempts for the given codes
+		AttemptsByCode map[EventCode]int
+		// DeadLetters receives the events MaxAttempts gave up on
+		DeadLetters DeadLetterSink
+		// Seen deduplicates redelivered events when set
+		Seen SeenStore
+		// Journal records every polled event with its outcome when set
+		Journal Journal
+		// SeenTTL is how long Seen remembers an event, DefaultSeenTTL when zero
+		SeenTTL time.Duration
+		// PruneInterval is the cadence Seen is pruned at, DefaultPruneInterval when zero
+		PruneInterval time.Duration
+		// Log defaults to logger.Nop
+		Log logger.Logger
+
+		wait      func(ctx context.Context, d time.Duration) error
+		lastPrune time.Time
+		failures  map[string]int
+	}
+)
+
+// Handle calls f(ctx, e)
+func (f HandlerFunc) Handle(ctx context.Context, e V2Event) error {
+	return f(ctx, e)
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{handlers: make(map[EventCode][]Handler)}
+}
+
+// Handle registers h for a Code or a Fullcode, handlers of
+// both run in the order they were registered
+func (r *Registry) Handle(code EventCode, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[code] = append(r.handlers[code], h)
+}
+
+// HandleFunc registers f for a Code or a Fullcode
+func (r *Registry) HandleFunc(code EventCode, f func(ctx context.Context, e V2Event) error) {
+	r.Handle(code, HandlerFunc(f))
+}
+
+// Default registers the handler of events no other handler is registered for
+func (r *Registry) Default(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = h
+}
+
+// Dispatch runs the handlers of e, stopping at the first error,
+// ErrNoHandler when there is none
+func (r *Registry) Dispatch(ctx context.Context, e V2Event) error {
+	r.mu.RLock()
+	handlers := append(append([]Handler{}, r.handlers[e.Code]...), r.handlers[EventCode(e.Fullcode)]...)
+	if len(handlers) == 0 && r.fallback != nil {
+		handlers = append(handlers, r.fallback)
+	}
+	r.mu.RUnlock()
+	if len(handlers) == 0 {
+		return ErrNoHandler
+	}
+	for _, h := range handlers {
+		if err := h.Handle(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// safeDispatch is Dispatch turning a handler panic into an ErrHandlerPanic error
+func (r *Registry) safeDispatch(ctx context.Context, e V2Event) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("%w: %v", ErrHandlerPanic, p)
+		}
+	}()
+	return r.Dispatch(ctx, e)
+}
+
+// NewConsumer returns a Consumer polling events at DefaultPollInterval
+func NewConsumer(events Service, registry *Registry) *Consumer {
+	return &Consumer{Events: events, Registry: registry}
+}
+
+// Run polls until ctx is done, the events handled before that are still
+// acknowledged, it returns the ctx error
+func (c *Consumer) Run(ctx context.Context) error {
+	log := c.Log
+	if log == nil {
+		log = logger.Nop()
+	}
+	backoff := time.Duration(0)
+	for {
+		c.prune(ctx, log)
+		wait := c.interval()
+		limited, err := c.poll(ctx, log)
+		switch {
+		case limited:
+			backoff = c.backoff(backoff)
+			wait = backoff
+			log.Warn("[SDK] (Event Consumer) rate limited, backing off", logger.F("wait", wait))
+		case err != nil:
+			backoff = 0
+			log.Error("[SDK] (Event Consumer) poll", logger.Err(err))
+		default:
+			backoff = 0
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err = c.sleep(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// poll handles one batch, limited tells whether iFood rate limited a call
+func (c *Consumer) poll(ctx context.Context, log logger.Logger) (limited bool, err error) {
+	polled, err := c.Events.V2PollMerchantsCtx(ctx, c.Merchants)
+	if err != nil {
+		limited = errors.Is(err, ErrReqLimitExceeded)
+		if len(polled) == 0 {
+			return limited, err
+		}
+		log.Error("[SDK] (Event Consumer) poll", logger.Err(err))
+		err = nil
+	}
+	received := time.Now()
+	var handled []V2Event
+	var entries []JournalEntry
+	for _, e := range polled {
+		entry := JournalEntry{Event: e, ReceivedAt: received, Outcome: OutcomeSkipped}
+		if ctx.Err() != nil {
+			entries = append(entries, entry)
+			continue
+		}
+		if alreadySeen(ctx, c.Seen, log, e) {
+			log.Debug("[SDK] (Event Consumer) already handled", logger.F("code", e.Code), logger.F("id", e.ID))
+			entry.Outcome, entry.Acked = OutcomeDuplicate, true
+			entries = append(entries, entry)
+			handled = append(handled, e)
+			continue
+		}
+		err := c.Registry.safeDispatch(ctx, e)
+		entry.Outcome, entry.Acked = OutcomeHandled, true
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		switch {
+		case err == nil:
+			delete(c.failures, e.ID)
+			markSeen(ctx, c.Seen, log, e)
+			handled = append(handled, e)
+		case errors.Is(err, ErrNoHandler) && c.AckUnhandled:
+			entry.Outcome = OutcomeUnhandled
+			handled = append(handled, e)
+		case errors.Is(err, ErrNoHandler):
+			log.Debug("[SDK] (Event Consumer) unhandled", logger.F("code", e.Code), logger.F("id", e.ID))
+			entry.Outcome, entry.Acked = OutcomeUnhandled, false
+		case c.giveUp(ctx, log, e, err):
+			entry.Outcome = OutcomeDeadLettered
+			handled = append(handled, e)
+		default:
+			log.Warn("[SDK] (Event Consumer) handler failed", logger.F("code", e.Code), logger.F("id", e.ID), logger.Err(err))
+			entry.Outcome, entry.Acked = OutcomeFailed, false
+		}
+		entries = append(entries, entry)
+	}
+	ackCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		ackCtx, cancel = context.WithTimeout(context.Background(), ackTimeout)
+		defer cancel()
+	}
+	if len(handled) > 0 {
+		if err = c.Events.V2AcknowledgeCtx(ackCtx, handled); err != nil {
+			limited = limited || errors.Is(err, ErrReqLimitExceeded)
+			for i := range entries {
+				if entries[i].Acked {
+					entries[i].Acked, entries[i].AckError = false, err.Error()
+				}
+			}
+		}
+	}
+	record(ackCtx, c.Journal, log, entries)
+	return limited, err
+}
+
+// giveUp counts the failed attempt of e, once the attempts run out
+// e is dead lettered and can be acknowledged
+func (c *Consumer) giveUp(ctx context.Context, log logger.Logger, e V2Event, err error) bool {
+	max := c.MaxAttempts
+	if n, ok := c.AttemptsByCode[e.Code]; ok {
+		max = n
+	}
+	if max <= 0 || c.DeadLetters == nil || e.ID == "" {
+		return false
+	}
+	if c.failures == nil {
+		c.failures = make(map[string]int)
+	}
+	c.failures[e.ID]++
+	attempts := c.failures[e.ID]
+	if attempts < max {
+		return false
+	}
+	dl := DeadLetter{Event: e, Attempts: attempts, Error: err.Error(), FailedAt: time.Now()}
+	if err = c.DeadLetters.Put(ctx, dl); err != nil {
+		log.Error("[SDK] (Event Consumer) DeadLetterSink.Put", logger.F("id", e.ID), logger.Err(err))
+		return false
+	}
+	delete(c.failures, e.ID)
+	log.Warn("[SDK] (Event Consumer) dead lettered", logger.F("code", e.Code), logger.F("id", e.ID), logger.F("attempts", attempts))
+	return true
+}
+
+// prune forgets the events older than SeenTTL once per PruneInterval
+func (c *Consumer) prune(ctx context.Context, log logger.Logger) {
+	if c.Seen == nil {
+		return
+	}
+	every, ttl := c.PruneInterval, c.SeenTTL
+	if every <= 0 {
+		every = DefaultPruneInterval
+	}
+	if ttl <= 0 {
+		ttl = DefaultSeenTTL
+	}
+	now := time.Now()
+	if now.Sub(c.lastPrune) < every {
+		return
+	}
+	c.lastPrune = now
+	if err := c.Seen.Prune(ctx, now.Add(-ttl)); err != nil {
+		log.Warn("[SDK] (Event Consumer) SeenStore.Prune", logger.Err(err))
+	}
+}
+
+func (c *Consumer) interval() time.Duration {
+	if c.Interval > 0 {
+		return c.Interval
+	}
+	return DefaultPollInterval
+}
+
+// backoff doubles the previous wait, starting from twice the interval
+func (c *Consumer) backoff(previous time.Duration) time.Duration {
+	max := c.MaxBackoff
+	if max <= 0 {
+		max = DefaultMaxBackoff
+	}
+	next := previous * 2
+	if previous == 0 {
+		next = c.interval() * 2
+	}
+	if next > max {
+		next = max
+	}
+	return next
+}
+
+func (c *Consumer) sleep(ctx context.Context, d time.Duration) error {
+	if c.wait != nil {
+		return c.wait(ctx, d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
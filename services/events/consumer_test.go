@@ -0,0 +1,184 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEvents serves one batch per poll, then cancels the consumer
+type fakeEvents struct {
+	Service
+	mu     sync.Mutex
+	polls  []func() ([]V2Event, error)
+	acked  [][]V2Event
+	cancel context.CancelFunc
+}
+
+func (f *fakeEvents) V2PollMerchantsCtx(ctx context.Context, merchantIDs []string) ([]V2Event, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.polls) == 0 {
+		f.cancel()
+		return nil, ctx.Err()
+	}
+	poll := f.polls[0]
+	f.polls = f.polls[1:]
+	return poll()
+}
+
+func (f *fakeEvents) V2AcknowledgeCtx(ctx context.Context, events []V2Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	f.acked = append(f.acked, events)
+	return nil
+}
+
+func batch(events ...V2Event) func() ([]V2Event, error) {
+	return func() ([]V2Event, error) { return events, nil }
+}
+
+func TestRegistry_Dispatch(t *testing.T) {
+	r := NewRegistry()
+	var calls []string
+	r.HandleFunc("PLC", func(ctx context.Context, e V2Event) error {
+		calls = append(calls, "code")
+		return nil
+	})
+	r.HandleFunc("PLACED", func(ctx context.Context, e V2Event) error {
+		calls = append(calls, "fullcode")
+		return nil
+	})
+	ctx := context.Background()
+	assert.Nil(t, r.Dispatch(ctx, V2Event{Code: "PLC", Fullcode: "PLACED"}))
+	assert.Equal(t, []string{"code", "fullcode"}, calls)
+	assert.Equal(t, ErrNoHandler, r.Dispatch(ctx, V2Event{Code: "CFM"}))
+	r.Default(HandlerFunc(func(ctx context.Context, e V2Event) error { return errors.New("failed") }))
+	assert.EqualError(t, r.Dispatch(ctx, V2Event{Code: "CFM"}), "failed")
+}
+
+func TestConsumer_AcksHandled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	plc := V2Event{ID: "1", Code: "PLC", Fullcode: "PLACED"}
+	can := V2Event{ID: "2", Code: "CAN", Fullcode: "CANCELLED"}
+	unknown := V2Event{ID: "3", Code: "XYZ"}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){
+		batch(plc, can, unknown),
+		batch(),
+	}}
+	r := NewRegistry()
+	r.HandleFunc("PLC", func(ctx context.Context, e V2Event) error { return nil })
+	r.HandleFunc("CANCELLED", func(ctx context.Context, e V2Event) error { return errors.New("db down") })
+	var waits []time.Duration
+	c := NewConsumer(events, r)
+	c.wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	assert.Equal(t, context.Canceled, c.Run(ctx))
+	assert.Equal(t, [][]V2Event{{plc}}, events.acked)
+	assert.Equal(t, []time.Duration{DefaultPollInterval, DefaultPollInterval}, waits)
+}
+
+func TestConsumer_AckUnhandled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	unknown := V2Event{ID: "3", Code: "XYZ"}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){batch(unknown)}}
+	c := NewConsumer(events, NewRegistry())
+	c.AckUnhandled = true
+	c.wait = func(ctx context.Context, d time.Duration) error { return nil }
+	c.Run(ctx)
+	assert.Equal(t, [][]V2Event{{unknown}}, events.acked)
+}
+
+func TestConsumer_BacksOffWhenRateLimited(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	limited := func() ([]V2Event, error) {
+		return nil, apierror.New("Events could not get polled", http.StatusTooManyRequests, "", nil)
+	}
+	failed := func() ([]V2Event, error) {
+		return nil, apierror.New("Events could not get polled", http.StatusInternalServerError, "", nil)
+	}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){
+		limited, limited, limited, limited, failed, limited,
+	}}
+	var waits []time.Duration
+	c := NewConsumer(events, NewRegistry())
+	c.Interval = time.Minute
+	c.wait = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	c.Run(ctx)
+	want := []time.Duration{2 * time.Minute, 4 * time.Minute, 5 * time.Minute, 5 * time.Minute, time.Minute, 2 * time.Minute}
+	assert.Equal(t, want, waits)
+}
+
+func TestConsumer_AcksAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	plc := V2Event{ID: "1", Code: "PLC"}
+	cfm := V2Event{ID: "2", Code: "CFM"}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){batch(plc, cfm)}}
+	r := NewRegistry()
+	r.HandleFunc("PLC", func(ctx context.Context, e V2Event) error {
+		cancel()
+		return nil
+	})
+	r.HandleFunc("CFM", func(ctx context.Context, e V2Event) error {
+		require.Fail(t, "dispatched after cancel")
+		return nil
+	})
+	c := NewConsumer(events, r)
+	assert.Equal(t, context.Canceled, c.Run(ctx))
+	assert.Equal(t, [][]V2Event{{plc}}, events.acked)
+}
+
+func TestConsumer_SkipsSeen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	plc := V2Event{ID: "1", Code: "PLC"}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){batch(plc), batch(plc)}}
+	handled := 0
+	r := NewRegistry()
+	r.HandleFunc("PLC", func(ctx context.Context, e V2Event) error {
+		handled++
+		return nil
+	})
+	c := NewConsumer(events, r)
+	c.Seen = NewMemorySeenStore(100, time.Hour)
+	c.wait = func(ctx context.Context, d time.Duration) error { return nil }
+	c.Run(ctx)
+	assert.Equal(t, 1, handled)
+	assert.Equal(t, [][]V2Event{{plc}, {plc}}, events.acked)
+}
+
+func TestConsumer_PrunesSeen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	seen := NewMemorySeenStore(0, 0)
+	require.Nil(t, seen.MarkSeen(ctx, "old", time.Now().Add(-2*time.Hour)))
+	require.Nil(t, seen.MarkSeen(ctx, "new", time.Now()))
+	events := &fakeEvents{cancel: cancel}
+	c := NewConsumer(events, NewRegistry())
+	c.Seen = seen
+	c.SeenTTL = time.Hour
+	c.Run(ctx)
+	old, _ := seen.Seen(ctx, "old")
+	assert.False(t, old)
+	recent, _ := seen.Seen(ctx, "new")
+	assert.True(t, recent)
+}
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type (
+	// DeadLetterSink keeps the events whose handlers kept failing,
+	// implementations must be safe for concurrent use
+	DeadLetterSink interface {
+		Put(ctx context.Context, dl DeadLetter) error
+		List(ctx context.Context) ([]DeadLetter, error)
+		// Remove drops the dead letter of the event id
+		Remove(ctx context.Context, id string) error
+	}
+
+	// DeadLetter is an event given up on, with the last handler error
+	DeadLetter struct {
+		Event    V2Event   `json:"event"`
+		Attempts int       `json:"attempts"`
+		Error    string    `json:"error"`
+		FailedAt time.Time `json:"failedAt"`
+	}
+
+	memoryDeadLetters struct {
+		mu      sync.Mutex
+		letters []DeadLetter
+	}
+
+	fileDeadLetters struct {
+		mu   sync.Mutex
+		path string
+	}
+)
+
+// NewMemoryDeadLetterSink returns a DeadLetterSink kept in memory
+func NewMemoryDeadLetterSink() DeadLetterSink {
+	return &memoryDeadLetters{}
+}
+
+func (m *memoryDeadLetters) Put(ctx context.Context, dl DeadLetter) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.letters = append(m.letters, dl)
+	return nil
+}
+
+func (m *memoryDeadLetters) List(ctx context.Context) ([]DeadLetter, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]DeadLetter(nil), m.letters...), nil
+}
+
+func (m *memoryDeadLetters) Remove(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.letters = withoutEvent(m.letters, id)
+	return nil
+}
+
+// NewFileDeadLetterSink returns a DeadLetterSink appending a JSON
+// line per dead letter to the file at path
+func NewFileDeadLetterSink(path string) DeadLetterSink {
+	return &fileDeadLetters{path: path}
+}
+
+func (f *fileDeadLetters) Put(ctx context.Context, dl DeadLetter) error {
+	line, err := json.Marshal(dl)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (f *fileDeadLetters) List(ctx context.Context) ([]DeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.read()
+}
+
+// Remove rewrites the file without the event and replaces it atomically
+func (f *fileDeadLetters) Remove(ctx context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	letters, err := f.read()
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	for _, dl := range withoutEvent(letters, id) {
+		line, err := json.Marshal(dl)
+		if err != nil {
+			return err
+		}
+		b.Write(append(line, '\n'))
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(b.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func (f *fileDeadLetters) read() (letters []DeadLetter, err error) {
+	data, err := ioutil.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxWebhookBody)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var dl DeadLetter
+		if err = json.Unmarshal(scanner.Bytes(), &dl); err != nil {
+			return nil, err
+		}
+		letters = append(letters, dl)
+	}
+	return letters, scanner.Err()
+}
+
+func withoutEvent(letters []DeadLetter, id string) (kept []DeadLetter) {
+	for _, dl := range letters {
+		if dl.Event.ID != id {
+			kept = append(kept, dl)
+		}
+	}
+	return
+}
+
+// Replay dispatches the dead lettered events to registry again, the
+// ones handled are removed from sink, it returns how many were
+func Replay(ctx context.Context, sink DeadLetterSink, registry *Registry) (replayed int, err error) {
+	letters, err := sink.List(ctx)
+	if err != nil {
+		return
+	}
+	for _, dl := range letters {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if registry.safeDispatch(ctx, dl.Event) != nil {
+			continue
+		}
+		if err = sink.Remove(ctx, dl.Event.ID); err != nil {
+			return
+		}
+		replayed++
+	}
+	return
+}
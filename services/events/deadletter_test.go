@@ -0,0 +1,140 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDeadLetterSink(t *testing.T, sink DeadLetterSink) {
+	ctx := context.Background()
+	letters, err := sink.List(ctx)
+	require.Nil(t, err)
+	assert.Empty(t, letters)
+	first := DeadLetter{Event: V2Event{ID: "1", Code: EventPlaced}, Attempts: 3, Error: "pos down", FailedAt: time.Now().UTC().Truncate(time.Second)}
+	second := DeadLetter{Event: V2Event{ID: "2", Code: EventCancelled}, Attempts: 3, Error: "pos down", FailedAt: first.FailedAt}
+	require.Nil(t, sink.Put(ctx, first))
+	require.Nil(t, sink.Put(ctx, second))
+	letters, err = sink.List(ctx)
+	require.Nil(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, first.Event.ID, letters[0].Event.ID)
+	assert.True(t, first.FailedAt.Equal(letters[0].FailedAt))
+	require.Nil(t, sink.Remove(ctx, "1"))
+	letters, err = sink.List(ctx)
+	require.Nil(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "2", letters[0].Event.ID)
+}
+
+func TestMemoryDeadLetterSink(t *testing.T) {
+	testDeadLetterSink(t, NewMemoryDeadLetterSink())
+}
+
+func TestFileDeadLetterSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "deadletters")
+	require.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	testDeadLetterSink(t, NewFileDeadLetterSink(filepath.Join(dir, "events.jsonl")))
+}
+
+func TestConsumer_DeadLetters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	plc := V2Event{ID: "1", Code: EventPlaced}
+	can := V2Event{ID: "2", Code: EventCancelled}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){
+		batch(plc, can), batch(plc, can), batch(plc, can),
+	}}
+	r := NewRegistry()
+	fail := func(ctx context.Context, e V2Event) error { return errors.New("pos down") }
+	r.HandleFunc(EventPlaced, fail)
+	r.HandleFunc(EventCancelled, fail)
+	sink := NewMemoryDeadLetterSink()
+	c := NewConsumer(events, r)
+	c.MaxAttempts = 3
+	c.AttemptsByCode = map[EventCode]int{EventCancelled: 2}
+	c.DeadLetters = sink
+	c.wait = func(ctx context.Context, d time.Duration) error { return nil }
+	c.Run(ctx)
+	assert.Equal(t, [][]V2Event{{can}, {plc}}, events.acked)
+	letters, err := sink.List(context.Background())
+	require.Nil(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, "pos down", letters[1].Error)
+
+	handled := 0
+	replay := NewRegistry()
+	replay.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error {
+		handled++
+		return nil
+	})
+	replay.HandleFunc(EventCancelled, fail)
+	replayed, err := Replay(context.Background(), sink, replay)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, replayed)
+	assert.Equal(t, 1, handled)
+	letters, _ = sink.List(context.Background())
+	require.Len(t, letters, 1)
+	assert.Equal(t, "2", letters[0].Event.ID)
+}
+
+func TestConsumer_HandlerPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	plc := V2Event{ID: "1", Code: EventPlaced}
+	cfm := V2Event{ID: "2", Code: EventConfirmed}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){
+		batch(plc, cfm), batch(plc),
+	}}
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error { panic("nil order") })
+	r.HandleFunc(EventConfirmed, func(ctx context.Context, e V2Event) error { return nil })
+	sink := NewMemoryDeadLetterSink()
+	c := NewConsumer(events, r)
+	c.MaxAttempts = 2
+	c.DeadLetters = sink
+	c.wait = func(ctx context.Context, d time.Duration) error { return nil }
+	assert.Equal(t, context.Canceled, c.Run(ctx))
+	assert.Equal(t, [][]V2Event{{cfm}, {plc}}, events.acked)
+	letters, err := sink.List(context.Background())
+	require.Nil(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, "event handler panicked: nil order", letters[0].Error)
+}
+
+func TestWebhook_DeadLetters(t *testing.T) {
+	r := NewRegistry()
+	var calls int32
+	fail := func(ctx context.Context, e V2Event) error {
+		atomic.AddInt32(&calls, 1)
+		return errors.New("pos down")
+	}
+	r.HandleFunc(EventPlaced, fail)
+	r.HandleFunc(EventCancelled, fail)
+	wh, err := NewWebhookWorkers("secret", r, NewMemoryDeadLetterSink(), 1, 8)
+	require.Nil(t, err)
+	var waits []time.Duration
+	wh.wait = func(ctx context.Context, d time.Duration) { waits = append(waits, d) }
+	wh.AttemptsByCode = map[EventCode]int{EventCancelled: 1}
+	require.Nil(t, wh.enqueue([]V2Event{{ID: "1", Code: EventPlaced}, {ID: "2", Code: EventCancelled}}))
+	require.Nil(t, wh.Shutdown(context.Background()))
+	assert.Equal(t, int32(DefaultWebhookAttempts+1), atomic.LoadInt32(&calls))
+	assert.Equal(t, []time.Duration{DefaultWebhookRetryBackoff, 2 * DefaultWebhookRetryBackoff}, waits)
+	letters, err := wh.DeadLetters.List(context.Background())
+	require.Nil(t, err)
+	require.Len(t, letters, 2)
+	assert.Equal(t, DefaultWebhookAttempts, letters[0].Attempts)
+	assert.Equal(t, "pos down", letters[0].Error)
+	assert.Equal(t, 1, letters[1].Attempts)
+}
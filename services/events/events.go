@@ -1,16 +1,16 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
-	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
-	"github.com/kpango/glg"
 )
 
 const (
@@ -19,20 +19,28 @@ const (
 	v2APIEndpoint = "/order/v1.0"
 )
 
-// ErrUnauthorized api error
-var ErrUnauthorized = errors.New("Unauthorized request")
+// ErrUnauthorized api error, matches an *apierror.APIError with errors.Is
+var ErrUnauthorized = apierror.ErrUnauthorized
 
-// ErrReqLimitExceeded API query limit exceeded
-var ErrReqLimitExceeded = errors.New("EVENTS POLL REQUEST LIMIT EXCEEDED")
-var ErrNotFound = errors.New("EVENTS POLL REQUEST RETURNED NOT FOUND")
+// ErrReqLimitExceeded API query limit exceeded, matches an *apierror.APIError with errors.Is
+var ErrReqLimitExceeded = apierror.ErrRateLimited
+
+// ErrNotFound matches an *apierror.APIError with errors.Is
+var ErrNotFound = apierror.ErrNotFound
 
 type (
 	// Service describes the event abstraction
 	Service interface {
 		Poll() ([]Event, error)
+		PollCtx(ctx context.Context) ([]Event, error)
 		V2Poll() (ml []V2Event, err error)
+		V2PollCtx(ctx context.Context) (ml []V2Event, err error)
+		V2PollMerchants(merchantIDs []string) ([]V2Event, error)
+		V2PollMerchantsCtx(ctx context.Context, merchantIDs []string) ([]V2Event, error)
 		Acknowledge([]Event) (err error)
+		AcknowledgeCtx(ctx context.Context, events []Event) (err error)
 		V2Acknowledge([]V2Event) (err error)
+		V2AcknowledgeCtx(ctx context.Context, events []V2Event) (err error)
 	}
 
 	eventACK struct {
@@ -57,15 +65,30 @@ type (
 		Createdat time.Time              `json:"createdAt"`
 		Fullcode  string                 `json:"fullCode"`
 		Metadata  map[string]interface{} `json:"metadata"`
-		Code      string                 `json:"code"`
+		Code      EventCode              `json:"code"`
 		Orderid   string                 `json:"orderId"`
 		ID        string                 `json:"id"`
+		// MerchantID is the merchant of the order, iFood sets it on the
+		// polled and the pushed events
+		MerchantID string `json:"merchantId,omitempty"`
 	}
 
+	// v2Ack is an event in the V2 acknowledgment body, iFood only
+	// needs its id
+	v2Ack struct {
+		ID string `json:"id"`
+	}
+
+	// ErrV2API is the iFood error body
+	//
+	// Deprecated: services return *apierror.APIError, which decodes this body
 	ErrV2API struct {
 		Error APIError `json:"error"`
 	}
 
+	// APIError is the error inside ErrV2API
+	//
+	// Deprecated: use apierror.APIError
 	APIError struct {
 		Code    string        `json:"code"`
 		Field   string        `json:"field"`
@@ -75,171 +98,141 @@ type (
 
 	eventService struct {
 		adapter adapters.Http
-		auth    auth.Service
 		v2      bool
+		log     logger.Logger
 	}
 )
 
 // New returns the event service implementation
 func New(adapter adapters.Http, authService auth.Service, v2 bool) Service {
-	return &eventService{adapter, authService, v2}
+	return &eventService{auth.Authorize(adapter, authService), v2, logger.Of(adapter)}
 }
 
 // Poll queries the iFood API for new events
 func (ev *eventService) Poll() (ml []Event, err error) {
-	err = ev.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Event auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", ev.auth.GetToken())
+	return ev.PollCtx(context.Background())
+}
+
+// PollCtx works like Poll, honoring ctx cancellation and deadlines
+func (ev *eventService) PollCtx(ctx context.Context) (ml []Event, err error) {
 	endpoint := v3Endpoint + ":polling"
-	resp, status, err := ev.adapter.DoRequest(
-		http.MethodGet, endpoint, nil, headers)
+	resp, status, err := ev.adapter.DoRequestCtx(ctx,
+		http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Event adapter.DoRequest: ", err.Error())
+		ev.log.Error("[SDK] Event adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status == http.StatusNotFound {
-		glg.Info("[SDK] Event adapter.DoRequest No events to poll")
-		return
-	}
-	if status == http.StatusTooManyRequests {
-		err = ErrReqLimitExceeded
-		glg.Warn("[SDK] Event adapter.DoRequest REQUEST LIMIT EXCEEDED")
-		return
-	}
-	if status == http.StatusUnauthorized {
-		err = ErrUnauthorized
-		glg.Warn("[SDK] Event adapter.DoRequest no auth")
+		ev.log.Info("[SDK] Event adapter.DoRequest No events to poll")
 		return
 	}
 	if status != http.StatusOK {
-		err = errors.New("Events could not get polled")
-		glg.Errorf("[SDK] Event adapter.DoRequest status '%d' err: %s", status, err.Error())
+		err = apierror.New("Events could not get polled", status, endpoint, resp)
+		ev.log.Error("[SDK] Event adapter.DoRequest", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Poll was successfull")
+	ev.log.Info("[SDK] Poll was successfull")
 	return ml, json.Unmarshal(resp, &ml)
 }
 
-// V2Poll queries the iFood API for new events
-// 		in the future we'll add a merchants param to allow filtering,
-// 		for now this works with <100 merchants
-// 			V2Poll(merchants []string)
-// 			req.Header.Set("X-Polling-Merchants", "m1,m2")
+// V2Poll queries the iFood API for new events of every merchant,
+// use V2PollMerchants with 100 merchants or more
 func (ev *eventService) V2Poll() (ml []V2Event, err error) {
-	err = ev.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] (Event V2Poll) auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", ev.auth.GetToken())
+	return ev.V2PollCtx(context.Background())
+}
+
+// V2PollCtx works like V2Poll, honoring ctx cancellation and deadlines
+func (ev *eventService) V2PollCtx(ctx context.Context) (ml []V2Event, err error) {
+	return ev.v2Poll(ctx, nil)
+}
+
+func (ev *eventService) v2Poll(ctx context.Context, headers map[string]string) (ml []V2Event, err error) {
 	endpoint := v2APIEndpoint + "/events:polling"
-	resp, status, err := ev.adapter.DoRequest(
+	resp, status, err := ev.adapter.DoRequestCtx(ctx,
 		http.MethodGet, endpoint, nil, headers)
 	if err != nil {
-		glg.Error("[SDK] (Event V2Poll) adapter.DoRequest: ", err.Error())
+		ev.log.Error("[SDK] (Event V2Poll) adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status == http.StatusNotFound {
-		glg.Info("[SDK] (Event V2Poll) adapter.DoRequest No events to poll")
-		return
-	}
-	if status == http.StatusForbidden {
-		err = ErrUnauthorized
-		glg.Warn("[SDK] (Event V2Poll) adapter.DoRequest no auth", err.Error())
+		ev.log.Info("[SDK] (Event V2Poll) adapter.DoRequest No events to poll")
 		return
 	}
 	if status != http.StatusOK {
-		errMsg := ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Errorf("[SDK] (Event V2Poll) adapter.DoRequest status '%d' api message:'%s'", status, errMsg.Error.Message)
+		err = apierror.New("Events could not get polled", status, endpoint, resp)
+		ev.log.Error("[SDK] (Event V2Poll) adapter.DoRequest", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] (V2Poll) was successfull")
+	ev.log.Info("[SDK] (V2Poll) was successfull")
 	return ml, json.Unmarshal(resp, &ml)
 }
 
 // Acknowledge queries the iFood API to set events as 'polled'
 func (ev *eventService) Acknowledge(events []Event) (err error) {
-	err = ev.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Event auth.Validate: ", err.Error())
-		return
-	}
+	return ev.AcknowledgeCtx(context.Background(), events)
+}
+
+// AcknowledgeCtx works like Acknowledge, honoring ctx cancellation and deadlines
+func (ev *eventService) AcknowledgeCtx(ctx context.Context, events []Event) (err error) {
 	eACK := []eventACK{}
 	for _, e := range events {
 		eACK = append(eACK, eventACK{e.ID})
 	}
 	reader, err := httpadapter.NewJsonReader(eACK)
 	if err != nil {
-		glg.Error("[SDK] Event NewJsonReader: ", err.Error())
+		ev.log.Error("[SDK] Event NewJsonReader", logger.Err(err))
 		return
 	}
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
 	headers["Cache-Control"] = "no-cache"
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", ev.auth.GetToken())
 	endpoint := v1Endpoint + "/acknowledgment"
-	_, status, err := ev.adapter.DoRequest(
+	resp, status, err := ev.adapter.DoRequestCtx(ctx,
 		http.MethodPost, endpoint, reader, headers)
 	if err != nil {
-		glg.Error("[SDK] Event adapter.DoRequest: ", err.Error())
-		return
-	}
-	if status == http.StatusUnauthorized {
-		glg.Warn("[SDK] Event AUTH error status code: ", status)
-		err = ErrUnauthorized
+		ev.log.Error("[SDK] Event adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		err = errors.New("Events could not get polled")
-		glg.Errorf("[SDK] Event Acknowledge status '%d' err: %s", status, err.Error())
+		err = apierror.New("Events could not get acknowledged", status, endpoint, resp)
+		ev.log.Error("[SDK] Event Acknowledge", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Acknowledge was successfull")
+	ev.log.Info("[SDK] Acknowledge was successfull")
 	return
 }
 
 // V2Acknowledge queries the iFood API to set events as 'polled'
 func (ev *eventService) V2Acknowledge(events []V2Event) (err error) {
-	err = ev.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] (Event V2ACK) auth.Validate: ", err.Error())
-		return
+	return ev.V2AcknowledgeCtx(context.Background(), events)
+}
+
+// V2AcknowledgeCtx works like V2Acknowledge, honoring ctx cancellation and deadlines
+func (ev *eventService) V2AcknowledgeCtx(ctx context.Context, events []V2Event) (err error) {
+	acks := make([]v2Ack, 0, len(events))
+	for _, e := range events {
+		acks = append(acks, v2Ack{ID: e.ID})
 	}
-	body, err := httpadapter.NewJsonReader(events)
+	body, err := httpadapter.NewJsonReader(acks)
 	if err != nil {
-		glg.Error("[SDK] (Event V2ACK) NewJsonReader: ", err.Error())
+		ev.log.Error("[SDK] (Event V2ACK) NewJsonReader", logger.Err(err))
 		return
 	}
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
 	headers["Cache-Control"] = "no-cache"
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", ev.auth.GetToken())
 	endpoint := v2APIEndpoint + "/acknowledgment"
-	resp, status, err := ev.adapter.DoRequest(
+	resp, status, err := ev.adapter.DoRequestCtx(ctx,
 		http.MethodPost, endpoint, body, headers)
 	if err != nil {
-		glg.Error("[SDK] (Event V2ACK) adapter.DoRequest: ", err.Error())
-		return
-	}
-	if status == http.StatusForbidden {
-		glg.Warn("[SDK] (Event V2ACK) AUTH error status code: ", status)
-		err = ErrUnauthorized
+		ev.log.Error("[SDK] (Event V2ACK) adapter.DoRequest", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		errMsg := ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Errorf("[SDK] (Event V2ACK) Acknowledge status '%d' err: %s", status, err.Error())
+		err = apierror.New("Events could not get acknowledged", status, endpoint, resp)
+		ev.log.Error("[SDK] (Event V2ACK) Acknowledge", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] (Event V2ACK) was successfull")
+	ev.log.Info("[SDK] (Event V2ACK) was successfull")
 	return
 }
@@ -1,14 +1,19 @@
 package events
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
 	"github.com/arxdsilva/golang-ifood-sdk/mocks"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 	"github.com/stretchr/testify/assert"
@@ -114,7 +119,7 @@ func TestPoll_Unauthorized(t *testing.T) {
 	events, err := eventsService.Poll()
 	assert.NotNil(t, err)
 	assert.Equal(t, 0, len(events))
-	assert.Equal(t, ErrUnauthorized, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
 func TestPoll_StatusTooManyRequests(t *testing.T) {
@@ -135,7 +140,7 @@ func TestPoll_StatusTooManyRequests(t *testing.T) {
 	events, err := eventsService.Poll()
 	assert.NotNil(t, err)
 	assert.Equal(t, 0, len(events))
-	assert.Equal(t, ErrReqLimitExceeded, err)
+	assert.ErrorIs(t, err, ErrReqLimitExceeded)
 }
 
 func TestPoll_StatusNotFound(t *testing.T) {
@@ -250,7 +255,7 @@ func TestAcknowledge_StatusUnauthorized(t *testing.T) {
 	assert.Nil(t, err)
 	err = eventsService.Acknowledge(events)
 	assert.NotNil(t, err)
-	assert.Equal(t, ErrUnauthorized, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
 func TestAcknowledge_StatusRequestEntityTooLarge(t *testing.T) {
@@ -276,7 +281,7 @@ func TestAcknowledge_StatusRequestEntityTooLarge(t *testing.T) {
 	assert.Nil(t, err)
 	err = eventsService.Acknowledge(events)
 	assert.NotNil(t, err)
-	assert.Contains(t, err.Error(), "not get polled")
+	assert.Contains(t, err.Error(), "not get acknowledged")
 }
 
 func Test_V2Poll_OK(t *testing.T) {
@@ -356,7 +361,7 @@ func Test_V2Poll_Forbidden(t *testing.T) {
 	events, err := eventsService.V2Poll()
 	assert.NotNil(t, err)
 	assert.Equal(t, 0, len(events))
-	assert.Equal(t, ErrUnauthorized, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
 func Test_V2Poll_StatusRequestEntityTooLarge(t *testing.T) {
@@ -386,7 +391,9 @@ func Test_V2Poll_StatusRequestEntityTooLarge(t *testing.T) {
 	events, err := eventsService.V2Poll()
 	assert.NotNil(t, err)
 	assert.Equal(t, 0, len(events))
-	assert.Equal(t, "too many merchants", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "too many merchants", apiErr.Message)
 }
 
 func Test_V2Poll_ValidateErr(t *testing.T) {
@@ -418,6 +425,10 @@ func Test_V2Acknowledge_OK(t *testing.T) {
 			require.NotNil(t, r.Header["Authorization"][0])
 			require.Equal(t, "/order/v1.0/acknowledgment", r.URL.Path)
 			require.Equal(t, r.Method, http.MethodPost)
+			body, err := ioutil.ReadAll(r.Body)
+			require.Nil(t, err)
+			require.NotContains(t, string(body), "merchantId")
+			require.Equal(t, `[{"id":"cd40582b-0ef2-4d52-bc7c-507fdff12e21"}]`, strings.TrimSpace(string(body)))
 			w.WriteHeader(http.StatusAccepted)
 		}),
 	)
@@ -431,6 +442,7 @@ func Test_V2Acknowledge_OK(t *testing.T) {
 	events := V2Events{}
 	err := json.Unmarshal([]byte(pollV2APIResponse), &events)
 	assert.Nil(t, err)
+	events[0].MerchantID = "merchant"
 	err = eventsService.V2Acknowledge(events)
 	assert.Nil(t, err)
 }
@@ -467,7 +479,9 @@ func Test_V2Acknowledge_BadRequest(t *testing.T) {
 	assert.Nil(t, err)
 	err = eventsService.V2Acknowledge(events)
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
 func Test_V2Acknowledge_Forbidden(t *testing.T) {
@@ -502,7 +516,7 @@ func Test_V2Acknowledge_Forbidden(t *testing.T) {
 	assert.Nil(t, err)
 	err = eventsService.V2Acknowledge(events)
 	assert.NotNil(t, err)
-	assert.Equal(t, ErrUnauthorized, err)
+	assert.ErrorIs(t, err, ErrUnauthorized)
 }
 
 func Test_V2Acknowledge_ValidateErr(t *testing.T) {
@@ -578,3 +592,25 @@ func Test_Poll_DoReqErr(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "some err", err.Error())
 }
+
+func Test_V2PollCtx_Canceled(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	service := New(adapter, &am, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+	events, err := service.V2PollCtx(ctx)
+	assert.Nil(t, events)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,308 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+const (
+	// DefaultSegmentSize is the size a journal segment file rolls over at
+	DefaultSegmentSize = 16 << 20
+	segmentPrefix      = "journal-"
+	segmentSuffix      = ".jsonl"
+)
+
+// Outcome is what happened to a journaled event
+type Outcome string
+
+// Outcomes of a journaled event
+const (
+	OutcomeHandled      Outcome = "handled"
+	OutcomeFailed       Outcome = "failed"
+	OutcomeUnhandled    Outcome = "unhandled"
+	OutcomeDuplicate    Outcome = "duplicate"
+	OutcomeDeadLettered Outcome = "dead_lettered"
+	// OutcomeSkipped the consumer stopped before handling the event
+	OutcomeSkipped Outcome = "skipped"
+)
+
+type (
+	// Journal records every event received, append only, implementations
+	// must be safe for concurrent use
+	Journal interface {
+		Record(ctx context.Context, entries ...JournalEntry) error
+		// Query returns the matching entries in the order they were recorded
+		Query(ctx context.Context, q JournalQuery) ([]JournalEntry, error)
+	}
+
+	// JournalEntry is an event as received, with the handler outcome
+	// and the acknowledgment result
+	JournalEntry struct {
+		Event      V2Event   `json:"event"`
+		ReceivedAt time.Time `json:"receivedAt"`
+		Outcome    Outcome   `json:"outcome"`
+		Error      string    `json:"error,omitempty"`
+		Acked      bool      `json:"acked"`
+		AckError   string    `json:"ackError,omitempty"`
+	}
+
+	// JournalQuery filters the journal, empty fields match everything,
+	// From is inclusive and To exclusive
+	JournalQuery struct {
+		OrderID    string
+		MerchantID string
+		From       time.Time
+		To         time.Time
+	}
+
+	memoryJournal struct {
+		mu      sync.Mutex
+		entries []JournalEntry
+	}
+
+	fileJournal struct {
+		mu          sync.Mutex
+		dir         string
+		segmentSize int64
+		current     string
+		size        int64
+	}
+)
+
+// Match tells whether the entry matches the query
+func (q JournalQuery) Match(e JournalEntry) bool {
+	switch {
+	case q.OrderID != "" && e.Event.Orderid != q.OrderID:
+		return false
+	case q.MerchantID != "" && e.Event.MerchantID != q.MerchantID:
+		return false
+	case !q.From.IsZero() && e.ReceivedAt.Before(q.From):
+		return false
+	case !q.To.IsZero() && !e.ReceivedAt.Before(q.To):
+		return false
+	}
+	return true
+}
+
+// NewMemoryJournal returns a Journal kept in memory
+func NewMemoryJournal() Journal {
+	return &memoryJournal{}
+}
+
+func (m *memoryJournal) Record(ctx context.Context, entries ...JournalEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func (m *memoryJournal) Query(ctx context.Context, q JournalQuery) (found []JournalEntry, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, e := range m.entries {
+		if q.Match(e) {
+			found = append(found, e)
+		}
+	}
+	return
+}
+
+// NewFileJournal returns a Journal appending a JSON line per entry to
+// segment files in dir, a segment is named after the receive time of its
+// first entry and rolls over once it reaches segmentSize bytes,
+// DefaultSegmentSize when zero
+func NewFileJournal(dir string, segmentSize int64) (Journal, error) {
+	if segmentSize <= 0 {
+		segmentSize = DefaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	f := &fileJournal{dir: dir, segmentSize: segmentSize}
+	segments, err := f.segments()
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) > 0 {
+		f.current = segments[len(segments)-1].path
+		info, err := os.Stat(f.current)
+		if err != nil {
+			return nil, err
+		}
+		f.size = info.Size()
+	}
+	return f, nil
+}
+
+func (f *fileJournal) Record(ctx context.Context, entries ...JournalEntry) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range entries {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		line = append(line, '\n')
+		if f.current == "" || f.size >= f.segmentSize {
+			f.current = f.segmentPath(e.ReceivedAt)
+			f.size = 0
+		}
+		file, err := os.OpenFile(f.current, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		n, err := file.Write(line)
+		f.size += int64(n)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		if err = file.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Query skips the segments outside of the queried time range
+func (f *fileJournal) Query(ctx context.Context, q JournalQuery) (found []JournalEntry, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	segments, err := f.segments()
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range segments {
+		if !q.To.IsZero() && !s.start.Before(q.To) {
+			break
+		}
+		if !q.From.IsZero() && i+1 < len(segments) && !segments[i+1].start.After(q.From) {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+		if found, err = readSegment(s.path, q, found); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+type segment struct {
+	path  string
+	start time.Time
+}
+
+// segments lists the segment files, oldest first
+func (f *fileJournal) segments() (segments []segment, err error) {
+	infos, err := ioutil.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range infos {
+		name := info.Name()
+		if info.IsDir() || !strings.HasPrefix(name, segmentPrefix) || !strings.HasSuffix(name, segmentSuffix) {
+			continue
+		}
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment{path: filepath.Join(f.dir, name), start: time.Unix(0, nanos)})
+	}
+	sort.Slice(segments, func(i, j int) bool { return segments[i].start.Before(segments[j].start) })
+	return
+}
+
+// segmentPath names a new segment, later than the current one so
+// segments stay ordered when the clock goes back
+func (f *fileJournal) segmentPath(start time.Time) string {
+	nanos := start.UnixNano()
+	if f.current != "" {
+		name := filepath.Base(f.current)
+		last, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimPrefix(name, segmentPrefix), segmentSuffix), 10, 64)
+		if err == nil && nanos <= last {
+			nanos = last + 1
+		}
+	}
+	return filepath.Join(f.dir, fmt.Sprintf("%s%020d%s", segmentPrefix, nanos, segmentSuffix))
+}
+
+func readSegment(path string, q JournalQuery, found []JournalEntry) ([]JournalEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxWebhookBody)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e JournalEntry
+		if err = json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, err
+		}
+		if q.Match(e) {
+			found = append(found, e)
+		}
+	}
+	return found, scanner.Err()
+}
+
+// ReplayJournal dispatches the events matching q to registry in the order
+// they were received, each event once, the events registry has no handler
+// for are skipped, it stops at the first handler error
+func ReplayJournal(ctx context.Context, journal Journal, q JournalQuery, registry *Registry) (replayed int, err error) {
+	entries, err := journal.Query(ctx, q)
+	if err != nil {
+		return
+	}
+	done := make(map[string]bool)
+	for _, e := range entries {
+		if done[e.Event.ID] {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		err = registry.safeDispatch(ctx, e.Event)
+		switch {
+		case errors.Is(err, ErrNoHandler):
+			err = nil
+			continue
+		case err != nil:
+			return replayed, fmt.Errorf("event %s: %w", e.Event.ID, err)
+		}
+		if e.Event.ID != "" {
+			done[e.Event.ID] = true
+		}
+		replayed++
+	}
+	return
+}
+
+// record journals the entries, a failing journal does not stop the events
+func record(ctx context.Context, journal Journal, log logger.Logger, entries []JournalEntry) {
+	if journal == nil || len(entries) == 0 {
+		return
+	}
+	if err := journal.Record(ctx, entries...); err != nil {
+		log.Warn("[SDK] (Event) Journal.Record", logger.F("entries", len(entries)), logger.Err(err))
+	}
+}
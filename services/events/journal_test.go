@@ -0,0 +1,150 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJournal(t *testing.T, journal Journal) {
+	ctx := context.Background()
+	start := time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, e := range []V2Event{
+		{ID: "1", Code: EventPlaced, Orderid: "order-a", MerchantID: "m1"},
+		{ID: "2", Code: EventPlaced, Orderid: "order-b", MerchantID: "m2"},
+		{ID: "3", Code: EventConfirmed, Orderid: "order-a", MerchantID: "m1"},
+		{ID: "3", Code: EventConfirmed, Orderid: "order-a", MerchantID: "m1"},
+	} {
+		entry := JournalEntry{Event: e, ReceivedAt: start.Add(time.Duration(i) * time.Minute), Outcome: OutcomeHandled, Acked: true}
+		require.Nil(t, journal.Record(ctx, entry))
+	}
+	all, err := journal.Query(ctx, JournalQuery{})
+	require.Nil(t, err)
+	require.Len(t, all, 4)
+	assert.Equal(t, OutcomeHandled, all[0].Outcome)
+	assert.True(t, start.Equal(all[0].ReceivedAt))
+
+	byOrder, err := journal.Query(ctx, JournalQuery{OrderID: "order-a"})
+	require.Nil(t, err)
+	assert.Len(t, byOrder, 3)
+	byMerchant, err := journal.Query(ctx, JournalQuery{MerchantID: "m2"})
+	require.Nil(t, err)
+	require.Len(t, byMerchant, 1)
+	assert.Equal(t, "2", byMerchant[0].Event.ID)
+	byTime, err := journal.Query(ctx, JournalQuery{From: start.Add(time.Minute), To: start.Add(3 * time.Minute)})
+	require.Nil(t, err)
+	require.Len(t, byTime, 2)
+	assert.Equal(t, "2", byTime[0].Event.ID)
+	assert.Equal(t, "3", byTime[1].Event.ID)
+
+	var replayed []string
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error {
+		replayed = append(replayed, e.ID)
+		return nil
+	})
+	r.HandleFunc(EventConfirmed, func(ctx context.Context, e V2Event) error {
+		replayed = append(replayed, e.ID)
+		return nil
+	})
+	n, err := ReplayJournal(ctx, journal, JournalQuery{OrderID: "order-a"}, r)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []string{"1", "3"}, replayed)
+}
+
+func TestMemoryJournal(t *testing.T) {
+	testJournal(t, NewMemoryJournal())
+}
+
+func TestFileJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "journal")
+	require.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	// a segment per entry
+	journal, err := NewFileJournal(dir, 1)
+	require.Nil(t, err)
+	testJournal(t, journal)
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Len(t, files, 4)
+
+	reopened, err := NewFileJournal(dir, 0)
+	require.Nil(t, err)
+	require.Nil(t, reopened.Record(context.Background(), JournalEntry{Event: V2Event{ID: "5"}, ReceivedAt: time.Now()}))
+	all, err := reopened.Query(context.Background(), JournalQuery{})
+	require.Nil(t, err)
+	assert.Len(t, all, 5)
+	// the last segment is appended to
+	files, _ = ioutil.ReadDir(dir)
+	assert.Len(t, files, 4)
+}
+
+func TestReplayJournal_HandlerError(t *testing.T) {
+	journal := NewMemoryJournal()
+	ctx := context.Background()
+	require.Nil(t, journal.Record(ctx,
+		JournalEntry{Event: V2Event{ID: "1", Code: EventPlaced}},
+		JournalEntry{Event: V2Event{ID: "2", Code: EventDispatched}},
+		JournalEntry{Event: V2Event{ID: "3", Code: EventConfirmed}},
+	))
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error { return nil })
+	r.HandleFunc(EventConfirmed, func(ctx context.Context, e V2Event) error { return errors.New("pos down") })
+	n, err := ReplayJournal(ctx, journal, JournalQuery{}, r)
+	assert.Equal(t, 1, n)
+	assert.EqualError(t, err, "event 3: pos down")
+}
+
+func TestConsumer_Journal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	plc := V2Event{ID: "1", Code: EventPlaced}
+	cfm := V2Event{ID: "2", Code: EventConfirmed}
+	dsp := V2Event{ID: "3", Code: EventDispatched}
+	events := &fakeEvents{cancel: cancel, polls: []func() ([]V2Event, error){batch(plc, cfm, dsp), batch(plc)}}
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error { return nil })
+	r.HandleFunc(EventConfirmed, func(ctx context.Context, e V2Event) error { return errors.New("pos down") })
+	journal := NewMemoryJournal()
+	c := NewConsumer(events, r)
+	c.Seen = NewMemorySeenStore(0, 0)
+	c.Journal = journal
+	c.wait = func(ctx context.Context, d time.Duration) error { return nil }
+	c.Run(ctx)
+	entries, err := journal.Query(context.Background(), JournalQuery{})
+	require.Nil(t, err)
+	require.Len(t, entries, 4)
+	var outcomes []Outcome
+	for _, e := range entries {
+		outcomes = append(outcomes, e.Outcome)
+	}
+	assert.Equal(t, []Outcome{OutcomeHandled, OutcomeFailed, OutcomeUnhandled, OutcomeDuplicate}, outcomes)
+	assert.True(t, entries[0].Acked)
+	assert.False(t, entries[1].Acked)
+	assert.Equal(t, "pos down", entries[1].Error)
+	assert.False(t, entries[2].Acked)
+	assert.True(t, entries[3].Acked)
+	assert.False(t, entries[0].ReceivedAt.IsZero())
+}
+
+func TestWebhook_Journal(t *testing.T) {
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error { return nil })
+	wh, err := NewWebhookWorkers("secret", r, NewMemoryDeadLetterSink(), 1, 8)
+	require.Nil(t, err)
+	wh.Journal = NewMemoryJournal()
+	require.Nil(t, wh.enqueue([]V2Event{{ID: "1", Code: EventPlaced, MerchantID: "m1"}, {ID: "2", Code: EventConfirmed}}))
+	require.Nil(t, wh.Shutdown(context.Background()))
+	entries, err := wh.Journal.Query(context.Background(), JournalQuery{MerchantID: "m1"})
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, OutcomeHandled, entries[0].Outcome)
+	assert.True(t, entries[0].Acked)
+}
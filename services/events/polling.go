@@ -0,0 +1,114 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+// MaxPollingMerchants is the most merchants a X-Polling-Merchants header takes
+const MaxPollingMerchants = 100
+
+type (
+	// BatchError is the failure of polling a batch of merchants
+	BatchError struct {
+		Merchants []string
+		Err       error
+	}
+
+	// PollErrors are the failed batches of V2PollMerchants,
+	// the events of the other batches are still returned
+	PollErrors []*BatchError
+)
+
+// V2PollMerchants polls the events of merchantIDs only, in batches of
+// MaxPollingMerchants polled concurrently, every merchant is polled
+// when merchantIDs is empty
+func (ev *eventService) V2PollMerchants(merchantIDs []string) ([]V2Event, error) {
+	return ev.V2PollMerchantsCtx(context.Background(), merchantIDs)
+}
+
+// V2PollMerchantsCtx works like V2PollMerchants, honoring ctx cancellation and deadlines
+func (ev *eventService) V2PollMerchantsCtx(ctx context.Context, merchantIDs []string) (ml []V2Event, err error) {
+	if len(merchantIDs) == 0 {
+		return ev.V2PollCtx(ctx)
+	}
+	batches := pollingBatches(merchantIDs)
+	results := make([][]V2Event, len(batches))
+	errs := make([]error, len(batches))
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			headers := map[string]string{"X-Polling-Merchants": strings.Join(batch, ",")}
+			results[i], errs[i] = ev.v2Poll(ctx, headers)
+		}(i, batch)
+	}
+	wg.Wait()
+	var failed PollErrors
+	for i, batch := range batches {
+		if errs[i] != nil {
+			failed = append(failed, &BatchError{Merchants: batch, Err: errs[i]})
+			continue
+		}
+		ml = append(ml, results[i]...)
+	}
+	if len(failed) > 0 {
+		ev.log.Error("[SDK] (Event V2PollMerchants)", logger.F("failed", len(failed)), logger.F("batches", len(batches)))
+		return ml, failed
+	}
+	return ml, nil
+}
+
+// pollingBatches splits the unique merchant ids in batches of MaxPollingMerchants
+func pollingBatches(merchantIDs []string) (batches [][]string) {
+	seen := make(map[string]bool, len(merchantIDs))
+	var batch []string
+	for _, id := range merchantIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		batch = append(batch, id)
+		if len(batch) == MaxPollingMerchants {
+			batches = append(batches, batch)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("polling %d merchants: %s", len(e.Merchants), e.Err.Error())
+}
+
+// Unwrap returns the polling error
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+func (e PollErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, b := range e {
+		msgs[i] = b.Error()
+	}
+	return fmt.Sprintf("%d polling batches failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// Is matches the error of any batch, e.g. ErrReqLimitExceeded
+func (e PollErrors) Is(target error) bool {
+	for _, b := range e {
+		if errors.Is(b, target) {
+			return true
+		}
+	}
+	return false
+}
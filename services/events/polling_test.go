@@ -0,0 +1,88 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pollingBatches(t *testing.T) {
+	ids := make([]string, 0, 252)
+	for i := 0; i < 250; i++ {
+		ids = append(ids, fmt.Sprintf("m%d", i))
+	}
+	ids = append(ids, "m0", "")
+	batches := pollingBatches(ids)
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], MaxPollingMerchants)
+	assert.Len(t, batches[1], MaxPollingMerchants)
+	assert.Len(t, batches[2], 50)
+}
+
+func Test_V2PollMerchants_PerBatchErrors(t *testing.T) {
+	var mu sync.Mutex
+	var polled []string
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			merchants := strings.Split(r.Header.Get("X-Polling-Merchants"), ",")
+			require.LessOrEqual(t, len(merchants), MaxPollingMerchants)
+			mu.Lock()
+			polled = append(polled, merchants...)
+			mu.Unlock()
+			if merchants[0] == "m100" {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			fmt.Fprintf(w, `[{"code":"PLC","fullCode":"PLACED","id":"%s"}]`, merchants[0])
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	eventsService := New(httpadapter.New(http.DefaultClient, ts.URL, httpadapter.WithRetryPolicy(httpadapter.NoRetry())), &am, true)
+	ids := make([]string, 250)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("m%d", i)
+	}
+	events, err := eventsService.V2PollMerchants(ids)
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrReqLimitExceeded))
+	var failed PollErrors
+	require.True(t, errors.As(err, &failed))
+	require.Len(t, failed, 1)
+	assert.Equal(t, ids[100:200], failed[0].Merchants)
+	got := []string{events[0].ID, events[1].ID}
+	sort.Strings(got)
+	assert.Equal(t, []string{"m0", "m200"}, got)
+	sort.Strings(polled)
+	sorted := append([]string{}, ids...)
+	sort.Strings(sorted)
+	assert.Equal(t, sorted, polled)
+}
+
+func Test_V2PollMerchants_NoFilter(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Empty(t, r.Header.Get("X-Polling-Merchants"))
+			fmt.Fprintf(w, pollV2APIResponse)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	events, err := New(httpadapter.New(http.DefaultClient, ts.URL), &am, true).V2PollMerchants(nil)
+	assert.Nil(t, err)
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,206 @@
+package events
+
+import (
+	"bufio"
+	"bytes"
+	"container/list"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+type (
+	// SeenStore remembers the events already handled, so the ones iFood
+	// redelivers are acknowledged without running the handlers again,
+	// implementations must be safe for concurrent use
+	SeenStore interface {
+		Seen(ctx context.Context, id string) (bool, error)
+		MarkSeen(ctx context.Context, id string, at time.Time) error
+		// Prune forgets the events marked before the given time
+		Prune(ctx context.Context, before time.Time) error
+	}
+
+	memorySeenStore struct {
+		mu       sync.Mutex
+		capacity int
+		ttl      time.Duration
+		order    *list.List
+		entries  map[string]*list.Element
+	}
+
+	seenEntry struct {
+		id string
+		at time.Time
+	}
+
+	fileSeenStore struct {
+		mu      sync.Mutex
+		path    string
+		entries map[string]time.Time
+	}
+)
+
+// NewMemorySeenStore returns a SeenStore keeping at most capacity events,
+// the least recently marked are evicted first, events marked longer
+// than ttl ago are no longer seen, zero disables either limit
+func NewMemorySeenStore(capacity int, ttl time.Duration) SeenStore {
+	return &memorySeenStore{capacity: capacity, ttl: ttl, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (m *memorySeenStore) Seen(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	el, ok := m.entries[id]
+	if !ok {
+		return false, nil
+	}
+	if m.ttl > 0 && time.Since(el.Value.(seenEntry).at) > m.ttl {
+		m.remove(el)
+		return false, nil
+	}
+	return true, nil
+}
+
+func (m *memorySeenStore) MarkSeen(ctx context.Context, id string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[id]; ok {
+		m.remove(el)
+	}
+	m.entries[id] = m.order.PushFront(seenEntry{id: id, at: at})
+	for m.capacity > 0 && m.order.Len() > m.capacity {
+		m.remove(m.order.Back())
+	}
+	return nil
+}
+
+func (m *memorySeenStore) Prune(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for el := m.order.Back(); el != nil; {
+		prev := el.Prev()
+		if el.Value.(seenEntry).at.Before(before) {
+			m.remove(el)
+		}
+		el = prev
+	}
+	return nil
+}
+
+func (m *memorySeenStore) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(seenEntry).id)
+}
+
+// NewFileSeenStore returns a SeenStore appending each event to the file
+// at path, one "id unix-nanos" line per event, Prune compacts the file
+func NewFileSeenStore(path string) (SeenStore, error) {
+	f := &fileSeenStore{path: path, entries: make(map[string]time.Time)}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		nanos, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		f.entries[fields[0]] = time.Unix(0, nanos)
+	}
+	return f, scanner.Err()
+}
+
+func (f *fileSeenStore) Seen(ctx context.Context, id string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.entries[id]
+	return ok, nil
+}
+
+func (f *fileSeenStore) MarkSeen(ctx context.Context, id string, at time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = fmt.Fprintf(file, "%s %d\n", id, at.UnixNano()); err != nil {
+		file.Close()
+		return err
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	f.entries[id] = at
+	return nil
+}
+
+// Prune rewrites the file with the remaining events and replaces it atomically
+func (f *fileSeenStore) Prune(ctx context.Context, before time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var b strings.Builder
+	kept := make(map[string]time.Time)
+	for id, at := range f.entries {
+		if at.Before(before) {
+			continue
+		}
+		kept[id] = at
+		fmt.Fprintf(&b, "%s %d\n", id, at.UnixNano())
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(f.path), filepath.Base(f.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), f.path); err != nil {
+		return err
+	}
+	f.entries = kept
+	return nil
+}
+
+// alreadySeen tells whether e was already handled, a failing
+// store lets the event be handled again
+func alreadySeen(ctx context.Context, store SeenStore, log logger.Logger, e V2Event) bool {
+	if store == nil || e.ID == "" {
+		return false
+	}
+	seen, err := store.Seen(ctx, e.ID)
+	if err != nil {
+		log.Warn("[SDK] (Event) SeenStore.Seen", logger.F("id", e.ID), logger.Err(err))
+	}
+	return seen
+}
+
+func markSeen(ctx context.Context, store SeenStore, log logger.Logger, e V2Event) {
+	if store == nil || e.ID == "" {
+		return
+	}
+	if err := store.MarkSeen(ctx, e.ID, time.Now()); err != nil {
+		log.Warn("[SDK] (Event) SeenStore.MarkSeen", logger.F("id", e.ID), logger.Err(err))
+	}
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySeenStore_LRU(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemorySeenStore(2, 0)
+	now := time.Now()
+	require.Nil(t, s.MarkSeen(ctx, "1", now))
+	require.Nil(t, s.MarkSeen(ctx, "2", now))
+	require.Nil(t, s.MarkSeen(ctx, "3", now))
+	seen, err := s.Seen(ctx, "1")
+	assert.Nil(t, err)
+	assert.False(t, seen)
+	seen, _ = s.Seen(ctx, "3")
+	assert.True(t, seen)
+}
+
+func TestMemorySeenStore_TTLAndPrune(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemorySeenStore(0, time.Hour)
+	now := time.Now()
+	require.Nil(t, s.MarkSeen(ctx, "old", now.Add(-2*time.Hour)))
+	require.Nil(t, s.MarkSeen(ctx, "recent", now.Add(-time.Minute)))
+	require.Nil(t, s.MarkSeen(ctx, "new", now))
+	seen, _ := s.Seen(ctx, "old")
+	assert.False(t, seen)
+	require.Nil(t, s.Prune(ctx, now.Add(-time.Second)))
+	seen, _ = s.Seen(ctx, "recent")
+	assert.False(t, seen)
+	seen, _ = s.Seen(ctx, "new")
+	assert.True(t, seen)
+}
+
+func TestFileSeenStore(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "seen")
+	require.Nil(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, "events.seen")
+	s, err := NewFileSeenStore(path)
+	require.Nil(t, err)
+	now := time.Now()
+	require.Nil(t, s.MarkSeen(ctx, "old", now.Add(-2*time.Hour)))
+	require.Nil(t, s.MarkSeen(ctx, "new", now))
+
+	reopened, err := NewFileSeenStore(path)
+	require.Nil(t, err)
+	seen, err := reopened.Seen(ctx, "old")
+	assert.Nil(t, err)
+	assert.True(t, seen)
+	require.Nil(t, reopened.Prune(ctx, now.Add(-time.Hour)))
+	seen, _ = reopened.Seen(ctx, "old")
+	assert.False(t, seen)
+
+	compacted, err := NewFileSeenStore(path)
+	require.Nil(t, err)
+	seen, _ = compacted.Seen(ctx, "old")
+	assert.False(t, seen)
+	seen, _ = compacted.Seen(ctx, "new")
+	assert.True(t, seen)
+	data, err := ioutil.ReadFile(path)
+	require.Nil(t, err)
+	assert.NotContains(t, string(data), "old")
+}
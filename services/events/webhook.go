@@ -0,0 +1,340 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+const (
+	// SignatureHeader carries the hex HMAC-SHA256 of the webhook body,
+	// keyed with the client secret
+	SignatureHeader = "X-IFood-Signature"
+	// maxWebhookBody bounds the body read from a webhook request
+	maxWebhookBody = 1 << 20
+	// DefaultWebhookWorkers is how many events a Webhook handles at once
+	DefaultWebhookWorkers = 4
+	// DefaultWebhookQueue is how many events a Webhook holds before refusing new ones
+	DefaultWebhookQueue = 256
+	// DefaultWebhookAttempts is how many times a Webhook handles a failing event
+	DefaultWebhookAttempts = 3
+	// DefaultWebhookRetryBackoff is the wait before the first retry of a failing event
+	DefaultWebhookRetryBackoff = time.Second
+)
+
+// ErrInvalidSignature the webhook body does not match its signature
+var ErrInvalidSignature = errors.New("webhook signature is invalid")
+
+// ErrWebhookClosed the webhook was shut down
+var ErrWebhookClosed = errors.New("webhook is shut down")
+
+// ErrWebhookQueueFull the handlers are behind, iFood delivers the events again
+var ErrWebhookQueueFull = errors.New("webhook queue is full")
+
+// ErrNoDeadLetterSink the webhook has nowhere to keep the events its handlers failed
+var ErrNoDeadLetterSink = errors.New("webhook needs a dead letter sink")
+
+// Webhook is an http.Handler receiving the events iFood pushes, the
+// signature is verified and the events are answered with 202 right
+// away, the Registry handlers run in the background. iFood does not
+// deliver an answered event again, the ones whose handlers keep failing
+// or panicking are put in DeadLetters
+//
+// A full queue answers 503 so iFood delivers the event again
+type Webhook struct {
+	Registry *Registry
+	// Seen deduplicates redelivered events when set, an event being
+	// handled is reserved so a concurrent delivery of it is a duplicate
+	Seen SeenStore
+	// MaxAttempts is how many times a failing event is handled before
+	// it is dead lettered, DefaultWebhookAttempts when zero
+	MaxAttempts int
+	// AttemptsByCode overrides MaxAttempts for the given codes
+	AttemptsByCode map[EventCode]int
+	// RetryBackoff is the wait before the first retry, doubled for the
+	// next ones, DefaultWebhookRetryBackoff when zero
+	RetryBackoff time.Duration
+	// DeadLetters receives the events whose handlers kept failing, they
+	// are not delivered again once answered
+	DeadLetters DeadLetterSink
+	// Journal records every event received with its outcome when set
+	Journal Journal
+	// Log defaults to logger.Nop
+	Log logger.Logger
+
+	secret []byte
+	wait   func(ctx context.Context, d time.Duration)
+	queue  chan queued
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+	// handling are the ids of the events reserved by a worker
+	handling map[string]bool
+}
+
+// queued is an event waiting for a worker
+type queued struct {
+	event    V2Event
+	received time.Time
+}
+
+// NewWebhook returns a Webhook verifying signatures with the client
+// secret, handling events with DefaultWebhookWorkers workers, it
+// returns ErrNoDeadLetterSink when deadLetters is nil
+func NewWebhook(clientSecret string, registry *Registry, deadLetters DeadLetterSink) (*Webhook, error) {
+	return NewWebhookWorkers(clientSecret, registry, deadLetters, DefaultWebhookWorkers, DefaultWebhookQueue)
+}
+
+// NewWebhookWorkers works like NewWebhook with the given workers and queue size
+func NewWebhookWorkers(clientSecret string, registry *Registry, deadLetters DeadLetterSink, workers, queue int) (*Webhook, error) {
+	if deadLetters == nil {
+		return nil, ErrNoDeadLetterSink
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	w := &Webhook{Registry: registry, DeadLetters: deadLetters, secret: []byte(clientSecret), queue: make(chan queued, queue)}
+	w.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go w.work()
+	}
+	return w, nil
+}
+
+// ServeHTTP implements http.Handler, the body is an event or a list of events
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !w.Verify(body, r.Header.Get(SignatureHeader)) {
+		w.log().Warn("[SDK] (Event Webhook) invalid signature")
+		http.Error(rw, ErrInvalidSignature.Error(), http.StatusUnauthorized)
+		return
+	}
+	received, err := decodeWebhook(body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err = w.enqueue(received); err != nil {
+		w.log().Warn("[SDK] (Event Webhook) events refused", logger.Err(err))
+		http.Error(rw, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	rw.WriteHeader(http.StatusAccepted)
+}
+
+// Verify tells whether signature is the hex HMAC-SHA256 of body
+func (w *Webhook) Verify(body []byte, signature string) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil || len(got) == 0 {
+		return false
+	}
+	return hmac.Equal(got, Sign(w.secret, body))
+}
+
+// Sign returns the HMAC-SHA256 of body keyed with secret
+func Sign(secret, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// Shutdown stops accepting events and waits for the queued ones
+// to be handled or ctx to be done
+func (w *Webhook) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue queues every event or none of them
+func (w *Webhook) enqueue(received []V2Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return ErrWebhookClosed
+	}
+	if w.DeadLetters == nil {
+		return ErrNoDeadLetterSink
+	}
+	if cap(w.queue)-len(w.queue) < len(received) {
+		return ErrWebhookQueueFull
+	}
+	now := time.Now()
+	for _, e := range received {
+		w.queue <- queued{event: e, received: now}
+	}
+	return nil
+}
+
+func (w *Webhook) work() {
+	defer w.wg.Done()
+	ctx := context.Background()
+	for q := range w.queue {
+		e, log := q.event, w.log()
+		// the 202 acknowledged the event
+		entry := JournalEntry{Event: e, ReceivedAt: q.received, Outcome: OutcomeDuplicate, Acked: true}
+		if !w.reserve(e) {
+			record(ctx, w.Journal, log, []JournalEntry{entry})
+			continue
+		}
+		if alreadySeen(ctx, w.Seen, log, e) {
+			w.release(e)
+			record(ctx, w.Journal, log, []JournalEntry{entry})
+			continue
+		}
+		attempts, err := w.dispatch(ctx, log, e)
+		switch {
+		case err == nil:
+			entry.Outcome = OutcomeHandled
+			markSeen(ctx, w.Seen, log, e)
+		case errors.Is(err, ErrNoHandler):
+			entry.Outcome = OutcomeUnhandled
+			log.Debug("[SDK] (Event Webhook) unhandled", logger.F("code", e.Code), logger.F("id", e.ID))
+		default:
+			entry.Outcome = OutcomeFailed
+			log.Error("[SDK] (Event Webhook) handler failed", logger.F("code", e.Code), logger.F("id", e.ID), logger.Err(err))
+			if w.deadLetter(ctx, log, e, attempts, err) {
+				entry.Outcome = OutcomeDeadLettered
+			}
+		}
+		w.release(e)
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		record(ctx, w.Journal, log, []JournalEntry{entry})
+	}
+}
+
+// reserve claims e for the calling worker before its handlers run,
+// false when another worker is handling it. Events are only reserved
+// when they are deduplicated, see Seen
+func (w *Webhook) reserve(e V2Event) bool {
+	if w.Seen == nil || e.ID == "" {
+		return true
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.handling[e.ID] {
+		return false
+	}
+	if w.handling == nil {
+		w.handling = make(map[string]bool)
+	}
+	w.handling[e.ID] = true
+	return true
+}
+
+// release drops the reservation of e once it is marked seen or its
+// handlers failed, so a later delivery is handled again
+func (w *Webhook) release(e V2Event) {
+	if w.Seen == nil || e.ID == "" {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.handling, e.ID)
+}
+
+// dispatch handles e until it succeeds or its attempts run out, waiting
+// RetryBackoff before the first retry and twice as long before each next one
+func (w *Webhook) dispatch(ctx context.Context, log logger.Logger, e V2Event) (attempts int, err error) {
+	max := w.MaxAttempts
+	if n, ok := w.AttemptsByCode[e.Code]; ok {
+		max = n
+	}
+	if max <= 0 {
+		max = DefaultWebhookAttempts
+	}
+	backoff := w.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultWebhookRetryBackoff
+	}
+	for attempts = 1; ; attempts++ {
+		err = w.Registry.safeDispatch(ctx, e)
+		if err == nil || errors.Is(err, ErrNoHandler) || attempts >= max {
+			return
+		}
+		log.Warn("[SDK] (Event Webhook) handler failed, retrying", logger.F("code", e.Code), logger.F("id", e.ID), logger.F("attempts", attempts), logger.Err(err))
+		w.sleep(ctx, backoff)
+		backoff *= 2
+	}
+}
+
+func (w *Webhook) sleep(ctx context.Context, d time.Duration) {
+	if w.wait != nil {
+		w.wait(ctx, d)
+		return
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+func (w *Webhook) deadLetter(ctx context.Context, log logger.Logger, e V2Event, attempts int, err error) bool {
+	if w.DeadLetters == nil {
+		return false
+	}
+	dl := DeadLetter{Event: e, Attempts: attempts, Error: err.Error(), FailedAt: time.Now()}
+	if err = w.DeadLetters.Put(ctx, dl); err != nil {
+		log.Error("[SDK] (Event Webhook) DeadLetterSink.Put", logger.F("id", e.ID), logger.Err(err))
+		return false
+	}
+	return true
+}
+
+func (w *Webhook) log() logger.Logger {
+	if w.Log == nil {
+		return logger.Nop()
+	}
+	return w.Log
+}
+
+func decodeWebhook(body []byte) (received []V2Event, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		err = json.Unmarshal(body, &received)
+		return
+	}
+	var e V2Event
+	if err = json.Unmarshal(body, &e); err != nil {
+		return
+	}
+	return []V2Event{e}, nil
+}
@@ -0,0 +1,182 @@
+package events
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedRequest(t *testing.T, secret, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/ifood/events", strings.NewReader(body))
+	req.Header.Set(SignatureHeader, hex.EncodeToString(Sign([]byte(secret), []byte(body))))
+	return req
+}
+
+func TestWebhook_DispatchesSignedEvents(t *testing.T) {
+	var mu sync.Mutex
+	var got []V2Event
+	done := make(chan struct{}, 2)
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error {
+		mu.Lock()
+		got = append(got, e)
+		mu.Unlock()
+		done <- struct{}{}
+		return nil
+	})
+	wh, err := NewWebhook("secret", r, NewMemoryDeadLetterSink())
+	require.Nil(t, err)
+	wh.Seen = NewMemorySeenStore(10, time.Hour)
+	ts := httptest.NewServer(wh)
+	defer ts.Close()
+
+	body := `{"id":"e1","code":"PLC","fullCode":"PLACED","orderId":"o1","merchantId":"m1"}`
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+	require.Nil(t, err)
+	req.Header.Set(SignatureHeader, hex.EncodeToString(Sign([]byte("secret"), []byte(body))))
+	resp, err := http.DefaultClient.Do(req)
+	require.Nil(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	<-done
+
+	rec := httptest.NewRecorder()
+	wh.ServeHTTP(rec, signedRequest(t, "secret", "["+body+`,{"id":"e2","code":"PLC"}]`))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	<-done
+	require.Nil(t, wh.Shutdown(context.Background()))
+	require.Len(t, got, 2)
+	assert.Equal(t, "m1", got[0].MerchantID)
+	assert.Equal(t, "e2", got[1].ID)
+}
+
+func TestWebhook_Refuses(t *testing.T) {
+	wh, err := NewWebhook("secret", NewRegistry(), NewMemoryDeadLetterSink())
+	require.Nil(t, err)
+	body := `{"id":"e1","code":"PLC"}`
+	cases := map[string]struct {
+		req    *http.Request
+		status int
+	}{
+		"method":    {httptest.NewRequest(http.MethodGet, "/", nil), http.StatusMethodNotAllowed},
+		"unsigned":  {httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), http.StatusUnauthorized},
+		"wrong key": {signedRequest(t, "other", body), http.StatusUnauthorized},
+		"payload":   {signedRequest(t, "secret", `{"id":`), http.StatusBadRequest},
+	}
+	for name, c := range cases {
+		rec := httptest.NewRecorder()
+		wh.ServeHTTP(rec, c.req)
+		assert.Equal(t, c.status, rec.Code, name)
+	}
+	require.Nil(t, wh.Shutdown(context.Background()))
+	rec := httptest.NewRecorder()
+	wh.ServeHTTP(rec, signedRequest(t, "secret", body))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestWebhook_QueueFull(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+	wh, err := NewWebhookWorkers("secret", r, NewMemoryDeadLetterSink(), 1, 1)
+	require.Nil(t, err)
+	body := `{"id":"e1","code":"PLC"}`
+	serve := func() int {
+		rec := httptest.NewRecorder()
+		wh.ServeHTTP(rec, signedRequest(t, "secret", body))
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusAccepted, serve())
+	<-started
+	assert.Equal(t, http.StatusAccepted, serve())
+	assert.Equal(t, http.StatusServiceUnavailable, serve())
+	close(release)
+	require.Nil(t, wh.Shutdown(context.Background()))
+}
+
+func TestNewWebhook_NeedsDeadLetterSink(t *testing.T) {
+	wh, err := NewWebhook("secret", NewRegistry(), nil)
+	assert.Nil(t, wh)
+	assert.Equal(t, ErrNoDeadLetterSink, err)
+}
+
+func TestWebhook_HandlerPanics(t *testing.T) {
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error { panic("nil order") })
+	sink := NewMemoryDeadLetterSink()
+	wh, err := NewWebhookWorkers("secret", r, sink, 1, 1)
+	require.Nil(t, err)
+	wh.MaxAttempts = 1
+	require.Nil(t, wh.enqueue([]V2Event{{ID: "1", Code: EventPlaced}}))
+	require.Nil(t, wh.Shutdown(context.Background()))
+	letters, err := sink.List(context.Background())
+	require.Nil(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, "event handler panicked: nil order", letters[0].Error)
+}
+
+// signalJournal passes the recorded entries to the test
+type signalJournal struct {
+	Journal
+	recorded chan JournalEntry
+}
+
+func (j signalJournal) Record(ctx context.Context, entries ...JournalEntry) error {
+	for _, e := range entries {
+		j.recorded <- e
+	}
+	return nil
+}
+
+func TestWebhook_ConcurrentRedelivery(t *testing.T) {
+	var calls int32
+	started, release := make(chan struct{}), make(chan struct{})
+	r := NewRegistry()
+	r.HandleFunc(EventPlaced, func(ctx context.Context, e V2Event) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+			<-release
+		}
+		if e.ID == "failing" {
+			return errors.New("pos down")
+		}
+		return nil
+	})
+	wh, err := NewWebhookWorkers("secret", r, NewMemoryDeadLetterSink(), 2, 4)
+	require.Nil(t, err)
+	wh.Seen = NewMemorySeenStore(10, time.Hour)
+	wh.MaxAttempts = 1
+	journal := signalJournal{recorded: make(chan JournalEntry)}
+	wh.Journal = journal
+
+	e := V2Event{ID: "1", Code: EventPlaced}
+	require.Nil(t, wh.enqueue([]V2Event{e}))
+	<-started
+	require.Nil(t, wh.enqueue([]V2Event{e}))
+	assert.Equal(t, OutcomeDuplicate, (<-journal.recorded).Outcome)
+	close(release)
+	assert.Equal(t, OutcomeHandled, (<-journal.recorded).Outcome)
+
+	// a failed event is released and handled again when delivered again
+	failing := V2Event{ID: "failing", Code: EventPlaced}
+	require.Nil(t, wh.enqueue([]V2Event{failing}))
+	assert.Equal(t, OutcomeDeadLettered, (<-journal.recorded).Outcome)
+	require.Nil(t, wh.enqueue([]V2Event{failing}))
+	assert.Equal(t, OutcomeDeadLettered, (<-journal.recorded).Outcome)
+	require.Nil(t, wh.Shutdown(context.Background()))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
@@ -1,6 +1,7 @@
 package merchant
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -8,8 +9,9 @@ import (
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
-	"github.com/kpango/glg"
 )
 
 const (
@@ -28,107 +30,113 @@ type (
 	// Service describes the merchant API abstraction
 	Service interface {
 		ListAll() ([]Merchant, error)
+		ListAllCtx(ctx context.Context) ([]Merchant, error)
 		Unavailabilities(merchantUUID string) (Unavailabilities, error)
+		UnavailabilitiesCtx(ctx context.Context, merchantUUID string) (Unavailabilities, error)
 		CreateUnavailabilityNow(merchantUUID, description string, pauseMinutes int32) (UnavailabilityResponse, error)
+		CreateUnavailabilityNowCtx(ctx context.Context, merchantUUID, description string, pauseMinutes int32) (UnavailabilityResponse, error)
 		DeleteUnavailability(merchantUUID, unavailabilityID string) error
+		DeleteUnavailabilityCtx(ctx context.Context, merchantUUID, unavailabilityID string) error
 		Availability(merchantUUID string) (AvailabilityResponse, error)
+		AvailabilityCtx(ctx context.Context, merchantUUID string) (AvailabilityResponse, error)
 	}
 
 	merchantService struct {
 		adapter adapters.Http
-		auth    auth.Service
+		log     logger.Logger
 	}
 )
 
 // New returns a new merchant service
 func New(adapter adapters.Http, authService auth.Service) *merchantService {
-	return &merchantService{adapter, authService}
+	return &merchantService{auth.Authorize(adapter, authService), logger.Of(adapter)}
 }
 
 // ListAll lista merchants cuja autenticacao tem permissao
 func (m *merchantService) ListAll() (ml []Merchant, err error) {
-	if err = m.auth.Validate(); err != nil {
-		glg.Error("[SDK] Merchant ListAll auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
-	resp, status, err := m.adapter.DoRequest(http.MethodGet,
-		v1Endpoint, nil, headers)
+	return m.ListAllCtx(context.Background())
+}
+
+// ListAllCtx works like ListAll, honoring ctx cancellation and deadlines
+func (m *merchantService) ListAllCtx(ctx context.Context) (ml []Merchant, err error) {
+	resp, status, err := m.adapter.DoRequestCtx(ctx, http.MethodGet,
+		v1Endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Merchant ListAll adapter.DoRequest error: ", err.Error())
+		m.log.Error("[SDK] Merchant ListAll adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Merchant ListAll status code: ", status)
-		err = errors.New("Could not list merchants")
-		glg.Error("[SDK] Merchant ListAll err: ", err)
+		m.log.Error("[SDK] Merchant ListAll", logger.F("status", status))
+		err = apierror.New("Could not list merchants", status, v1Endpoint, resp)
+		m.log.Error("[SDK] Merchant ListAll err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Merchant ListAll success")
+	m.log.Info("[SDK] Merchant ListAll success")
 	return ml, json.Unmarshal(resp, &ml)
 }
 
 // Unavailabilities lista indisponibilidades do merchant
 func (m *merchantService) Unavailabilities(merchantUUID string) (mu Unavailabilities, err error) {
+	return m.UnavailabilitiesCtx(context.Background(), merchantUUID)
+}
+
+// UnavailabilitiesCtx works like Unavailabilities, honoring ctx cancellation and deadlines
+func (m *merchantService) UnavailabilitiesCtx(ctx context.Context, merchantUUID string) (mu Unavailabilities, err error) {
 	if merchantUUID == "" {
 		err = ErrMerchantNotSpecified
-		glg.Error("[SDK] Merchant Unavailabilities: ", err.Error())
-		return
-	}
-	if err = m.auth.Validate(); err != nil {
-		glg.Error("[SDK] Merchant Unavailabilities auth.Validate: ", err.Error())
+		m.log.Error("[SDK] Merchant Unavailabilities", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/unavailabilities", v1Endpoint, merchantUUID)
-	resp, status, err := m.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := m.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, headers)
 	if err != nil {
-		glg.Error("[SDK] Merchant Unavailabilities adapter.DoRequest error: ", err.Error())
+		m.log.Error("[SDK] Merchant Unavailabilities adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Merchant Unavailabilities status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not get 'unavailabilities'", merchantUUID)
-		glg.Error("[SDK] Merchant Unavailabilities err: ", err)
+		m.log.Error("[SDK] Merchant Unavailabilities", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not get 'unavailabilities'", merchantUUID), status, endpoint, resp)
+		m.log.Error("[SDK] Merchant Unavailabilities err", logger.Err(err))
 		return
 	}
-	glg.Info("[SDK] Merchant Unavailabilities success")
+	m.log.Info("[SDK] Merchant Unavailabilities success")
 	return mu, json.Unmarshal(resp, &mu)
 }
 
 // CreateUnavailabilityNow cadastra indisponibilidade no merchant
 func (m *merchantService) CreateUnavailabilityNow(merchantUUID, description string, pauseMinutes int32) (ur UnavailabilityResponse, err error) {
+	return m.CreateUnavailabilityNowCtx(context.Background(), merchantUUID, description, pauseMinutes)
+}
+
+// CreateUnavailabilityNowCtx works like CreateUnavailabilityNow, honoring ctx cancellation and deadlines
+func (m *merchantService) CreateUnavailabilityNowCtx(ctx context.Context, merchantUUID, description string, pauseMinutes int32) (ur UnavailabilityResponse, err error) {
 	if merchantUUID == "" {
 		err = ErrMerchantNotSpecified
-		glg.Error("[SDK] Merchant CreateUnavailabilityNow: ", err.Error())
-		return
-	}
-	if err = m.auth.Validate(); err != nil {
-		glg.Error("[SDK] Merchant CreateUnavailabilityNow auth.Validate: ", err.Error())
+		m.log.Error("[SDK] Merchant CreateUnavailabilityNow", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/unavailabilities:now", v1Endpoint, merchantUUID)
 	unv := unavailability{Description: description, Minutes: pauseMinutes}
 	reader, err := httpadapter.NewJsonReader(unv)
 	if err != nil {
-		glg.Error("[SDK] Merchant CreateUnavailabilityNow NewJsonReader error: ", err.Error())
+		m.log.Error("[SDK] Merchant CreateUnavailabilityNow NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := m.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	resp, status, err := m.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, reader, headers)
 	if err != nil {
-		glg.Error("[SDK] Merchant CreateUnavailabilityNow adapter.DoRequest error: ", err.Error())
+		m.log.Error("[SDK] Merchant CreateUnavailabilityNow adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Merchant CreateUnavailabilityNow status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not create 'unavailability'", merchantUUID)
-		glg.Error("[SDK] Merchant CreateUnavailabilityNow err: ", err)
+		m.log.Error("[SDK] Merchant CreateUnavailabilityNow", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not create 'unavailability'", merchantUUID), status, endpoint, resp)
+		m.log.Error("[SDK] Merchant CreateUnavailabilityNow err", logger.Err(err))
 		return
 	}
 	return ur, json.Unmarshal(resp, &ur)
@@ -136,28 +144,29 @@ func (m *merchantService) CreateUnavailabilityNow(merchantUUID, description stri
 
 // DeleteUnavailability remove indisponibilidade no merchant
 func (m *merchantService) DeleteUnavailability(merchantUUID, unavailabilityID string) (err error) {
+	return m.DeleteUnavailabilityCtx(context.Background(), merchantUUID, unavailabilityID)
+}
+
+// DeleteUnavailabilityCtx works like DeleteUnavailability, honoring ctx cancellation and deadlines
+func (m *merchantService) DeleteUnavailabilityCtx(ctx context.Context, merchantUUID, unavailabilityID string) (err error) {
 	if (merchantUUID == "") || (unavailabilityID == "") {
 		err = ErrMerchantORUnavailabilityIDNotSpecified
-		glg.Error("[SDK] Merchant DeleteUnavailability: ", err.Error())
-		return
-	}
-	if err = m.auth.Validate(); err != nil {
-		glg.Error("[SDK] Merchant DeleteUnavailability auth.Validate: ", err.Error())
+		m.log.Error("[SDK] Merchant DeleteUnavailability", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/unavailabilities/%s", v1Endpoint, merchantUUID, unavailabilityID)
-	_, status, err := m.adapter.DoRequest(http.MethodDelete, endpoint, nil, headers)
+	resp, status, err := m.adapter.DoRequestCtx(ctx, http.MethodDelete, endpoint, nil, headers)
 	if err != nil {
-		glg.Error("[SDK] Merchant DeleteUnavailability adapter.DoRequest error: ", err.Error())
+		m.log.Error("[SDK] Merchant DeleteUnavailability adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Merchant DeleteUnavailability status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not delete unavailability id '%s' ", merchantUUID, unavailabilityID)
-		glg.Error("[SDK] Merchant DeleteUnavailability err: ", err)
+		m.log.Error("[SDK] Merchant DeleteUnavailability", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not delete unavailability id '%s' ", merchantUUID, unavailabilityID), status, endpoint, resp)
+		m.log.Error("[SDK] Merchant DeleteUnavailability err", logger.Err(err))
 		return
 	}
 	return
@@ -165,28 +174,29 @@ func (m *merchantService) DeleteUnavailability(merchantUUID, unavailabilityID st
 
 // Availability recebe o status de disponibilidade de um merchant
 func (m *merchantService) Availability(merchantUUID string) (ar AvailabilityResponse, err error) {
+	return m.AvailabilityCtx(context.Background(), merchantUUID)
+}
+
+// AvailabilityCtx works like Availability, honoring ctx cancellation and deadlines
+func (m *merchantService) AvailabilityCtx(ctx context.Context, merchantUUID string) (ar AvailabilityResponse, err error) {
 	if merchantUUID == "" {
 		err = ErrMerchantNotSpecified
-		glg.Error("[SDK] Merchant Availability: ", err.Error())
-		return
-	}
-	if err = m.auth.Validate(); err != nil {
-		glg.Error("[SDK] Merchant Availability auth.Validate: ", err.Error())
+		m.log.Error("[SDK] Merchant Availability", logger.Err(err))
 		return
 	}
+	ctx = auth.WithMerchant(ctx, merchantUUID)
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", m.auth.GetToken())
 	endpoint := fmt.Sprintf("/merchant%s/%s/availabilities", v2Endpoint, merchantUUID)
-	resp, status, err := m.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := m.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, headers)
 	if err != nil {
-		glg.Error("[SDK] Merchant Availability adapter.DoRequest error: ", err.Error())
+		m.log.Error("[SDK] Merchant Availability adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Merchant Availability status code: ", status, " merchant: ", merchantUUID)
-		err = fmt.Errorf("Merchant '%s' could not get availability", merchantUUID)
-		glg.Error("[SDK] Merchant Availability err: ", err)
+		m.log.Error("[SDK] Merchant Availability", logger.F("status", status), logger.F("merchantUUID", merchantUUID))
+		err = apierror.New(fmt.Sprintf("Merchant '%s' could not get availability", merchantUUID), status, endpoint, resp)
+		m.log.Error("[SDK] Merchant Availability err", logger.Err(err))
 		return
 	}
 	return ar, json.Unmarshal(resp, &ar)
@@ -7,4 +7,15 @@ var (
 	ErrOrderReferenceNotSpecified = errors.New("Order reference not specified")
 	// ErrCancelCodeNotSpecified no cancel code provided
 	ErrCancelCodeNotSpecified = errors.New("Order cancel code not specified")
+	// ErrCancelCodeNotAllowed the order does not accept the cancel code
+	ErrCancelCodeNotAllowed = errors.New("Order cancel code not allowed")
+	// ErrDisputeNotSpecified no dispute id provided
+	ErrDisputeNotSpecified = errors.New("Dispute id not specified")
+	// ErrDisputeReasonNotSpecified rejecting a dispute needs a reason
+	ErrDisputeReasonNotSpecified = errors.New("Dispute reason not specified")
+	// ErrInvalidTransition the action is not allowed in the order state,
+	// returned as a *TransitionError
+	ErrInvalidTransition = errors.New("order transition is not allowed")
+	// ErrOrderMismatch the event belongs to another order
+	ErrOrderMismatch = errors.New("event belongs to another order")
 )
@@ -1,27 +1,32 @@
 package orders
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/arxdsilva/golang-ifood-sdk/adapters"
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+	"github.com/arxdsilva/golang-ifood-sdk/money"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
-	"github.com/arxdsilva/golang-ifood-sdk/services/events"
-	"github.com/kpango/glg"
 )
 
 const (
-	v1Endpoint    = "/v1.0/orders"
-	v2Endpoint    = "/v2.0/orders"
-	v3Endpoint    = "/v3.0/orders"
-	newV2Endpoint = "/order/v1.0/orders/"
+	v1Endpoint       = "/v1.0/orders"
+	v2Endpoint       = "/v2.0/orders"
+	v3Endpoint       = "/v3.0/orders"
+	newV2Endpoint    = "/order/v1.0/orders/"
+	disputesEndpoint = "/order/v1.0/disputes/"
 )
 
 var (
-	// CancelCodes are all valid iFood API cancellation codes
+	// CancelCodes are the iFood API cancellation codes, V2 orders are
+	// checked against their V2CancellationReasons, these are only used
+	// when iFood cannot be reached
 	CancelCodes = map[string]string{
 		"501": "PROBLEMAS DE SISTEMA",
 		"502": "PEDIDO EM DUPLICIDADE",
@@ -52,371 +57,430 @@ type (
 	// Service determinates the order's interface
 	Service interface {
 		GetDetails(reference string) (OrderDetails, error)
+		GetDetailsCtx(ctx context.Context, reference string) (OrderDetails, error)
 		V2GetDetails(reference string) (V2OrderDetails, error)
+		V2GetDetailsCtx(ctx context.Context, reference string) (V2OrderDetails, error)
 		SetIntegrateStatus(reference string) error
+		SetIntegrateStatusCtx(ctx context.Context, reference string) error
 		SetConfirmStatus(reference string) error
+		SetConfirmStatusCtx(ctx context.Context, reference string) error
 		V2SetConfirmStatus(reference string) error
+		V2SetConfirmStatusCtx(ctx context.Context, reference string) error
 		SetDispatchStatus(reference string) error
+		SetDispatchStatusCtx(ctx context.Context, reference string) error
 		V2SetDispatchStatus(reference string) error
+		V2SetDispatchStatusCtx(ctx context.Context, reference string) error
 		SetReadyToDeliverStatus(reference string) error
+		SetReadyToDeliverStatusCtx(ctx context.Context, reference string) error
 		V2SetReadyToPickupStatus(reference string) error
+		V2SetReadyToPickupStatusCtx(ctx context.Context, reference string) error
 		SetCancelStatus(reference, code string) error
+		SetCancelStatusCtx(ctx context.Context, reference, code string) error
 		V2RequestCancelStatus(reference, code string) error
+		V2RequestCancelStatusCtx(ctx context.Context, reference, code string) error
 		ClientCancellationStatus(reference string, accepted bool) error
+		ClientCancellationStatusCtx(ctx context.Context, reference string, accepted bool) error
 		V2ClientCancellationStatus(reference string, accepted bool) error
+		V2ClientCancellationStatusCtx(ctx context.Context, reference string, accepted bool) error
 		Tracking(orderUUID string) (TrackingResponse, error)
+		TrackingCtx(ctx context.Context, orderUUID string) (TrackingResponse, error)
 		DeliveryInformation(orderUUID string) (DeliveryInformationResponse, error)
+		DeliveryInformationCtx(ctx context.Context, orderUUID string) (DeliveryInformationResponse, error)
+		V2StartPreparation(reference string) error
+		V2StartPreparationCtx(ctx context.Context, reference string) error
+		V2RequestDriver(reference string) error
+		V2RequestDriverCtx(ctx context.Context, reference string) error
+		V2CancellationReasons(reference string) ([]CancellationReason, error)
+		V2CancellationReasonsCtx(ctx context.Context, reference string) ([]CancellationReason, error)
+		V2AcceptDispute(disputeID, reason string) error
+		V2AcceptDisputeCtx(ctx context.Context, disputeID, reason string) error
+		V2RejectDispute(disputeID, reason string) error
+		V2RejectDisputeCtx(ctx context.Context, disputeID, reason string) error
+		V2Tracking(reference string) (TrackingResponse, error)
+		V2TrackingCtx(ctx context.Context, reference string) (TrackingResponse, error)
+		V2DeliveryInformation(reference string) (DeliveryInformationResponse, error)
+		V2DeliveryInformationCtx(ctx context.Context, reference string) (DeliveryInformationResponse, error)
 	}
 
 	ordersService struct {
 		adapter adapters.Http
-		auth    auth.Service
+		log     logger.Logger
+		reasons *reasonsCache
 	}
 )
 
-// New returns a new order service
+// New returns a new order service, with a Resolver such as a Manager
+// as authService the calls need the merchant set with
+// authentication.WithMerchant
 func New(adapter adapters.Http, authService auth.Service) Service {
-	return &ordersService{adapter, authService}
+	return &ordersService{auth.AuthorizeMerchant(adapter, authService), logger.Of(adapter), newReasonsCache()}
 }
 
+// GetDetails returns the order, prices that are not decimals are left at
+// zero, OrderDetails.AmountErrors reports them
 func (o *ordersService) GetDetails(orderReference string) (od OrderDetails, err error) {
+	return o.GetDetailsCtx(context.Background(), orderReference)
+}
+
+// GetDetailsCtx works like GetDetails, honoring ctx cancellation and deadlines
+func (o *ordersService) GetDetailsCtx(ctx context.Context, orderReference string) (od OrderDetails, err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders GetDetails: ", err.Error())
+		o.log.Error("[SDK] Orders GetDetails", logger.Err(err))
 		return
 	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Orders GetDetails auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s", v3Endpoint, orderReference)
-	resp, status, err := o.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders GetDetails adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders GetDetails adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		glg.Error("[SDK] Orders GetDetails status code: ", status)
-		err = fmt.Errorf("Order reference '%s' could not retrieve details", orderReference)
-		glg.Error("[SDK] Orders GetDetails err: ", err)
+		o.log.Error("[SDK] Orders GetDetails", logger.F("status", status))
+		err = apierror.New(fmt.Sprintf("Order reference '%s' could not retrieve details", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] Orders GetDetails err", logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders GetDetails) OK", logger.F("orderReference", orderReference))
+	if err = json.Unmarshal(resp, &od); err != nil {
 		return
 	}
-	glg.Debugf("[SDK] (Orders GetDetails) '%s' OK", orderReference)
-	return od, json.Unmarshal(resp, &od)
+	if amountErr := od.AmountErrors(); amountErr != nil {
+		o.log.Warn("[SDK] (Orders GetDetails) malformed amounts", logger.F("orderReference", orderReference), logger.Err(amountErr))
+	}
+	return
 }
 
+// V2GetDetails returns the order, the prices are in the currency of its
+// payments and, like GetDetails, malformed ones are left at zero,
+// V2OrderDetails.AmountErrors reports them
 func (o *ordersService) V2GetDetails(orderUUID string) (od V2OrderDetails, err error) {
+	return o.V2GetDetailsCtx(context.Background(), orderUUID)
+}
+
+// V2GetDetailsCtx works like V2GetDetails, honoring ctx cancellation and deadlines
+func (o *ordersService) V2GetDetailsCtx(ctx context.Context, orderUUID string) (od V2OrderDetails, err error) {
 	if orderUUID == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] (Orders V2GetDetails): ", err.Error())
+		o.log.Error("[SDK] (Orders V2GetDetails)", logger.Err(err))
 		return
 	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] (Orders V2GetDetails) auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s%s", newV2Endpoint, orderUUID)
-	resp, status, err := o.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] (Orders V2GetDetails) adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] (Orders V2GetDetails) adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusOK {
-		errMsg := events.ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Error("[SDK] (Orders V2GetDetails) status '%d' err: '%s'", status, err.Error())
+		err = apierror.New(fmt.Sprintf("Order '%s' could not retrieve details", orderUUID), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2GetDetails)", logger.F("status", status), logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders V2GetDetails) OK", logger.F("orderUUID", orderUUID))
+	if err = json.Unmarshal(resp, &od); err != nil {
 		return
 	}
-	glg.Debugf("[SDK] (Orders V2GetDetails) '%s' OK", orderUUID)
-	return od, json.Unmarshal(resp, &od)
+	od.setCurrency()
+	if amountErr := od.AmountErrors(); amountErr != nil {
+		o.log.Warn("[SDK] (Orders V2GetDetails) malformed amounts", logger.F("orderUUID", orderUUID), logger.Err(amountErr))
+	}
+	return
+}
+
+// AmountErrors returns a *money.AmountError listing the prices of the
+// order that were not decimals, nil when there is none
+func (od *OrderDetails) AmountErrors() error {
+	return money.Validate(od)
+}
+
+// AmountErrors works like OrderDetails.AmountErrors
+func (od *V2OrderDetails) AmountErrors() error {
+	return money.Validate(od)
+}
+
+// setCurrency sets the currency of each payment method on its amounts,
+// the other amounts take the currency of the first method
+func (od *V2OrderDetails) setCurrency() {
+	currency := ""
+	for i := range od.Payments.Methods {
+		m := &od.Payments.Methods[i]
+		if m.Currency == "" {
+			continue
+		}
+		if currency == "" {
+			currency = m.Currency
+		}
+		money.SetCurrency(m, m.Currency)
+	}
+	if currency != "" {
+		money.SetCurrency(od, currency)
+	}
 }
 
 func (o *ordersService) SetIntegrateStatus(orderReference string) (err error) {
+	return o.SetIntegrateStatusCtx(context.Background(), orderReference)
+}
+
+// SetIntegrateStatusCtx works like SetIntegrateStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) SetIntegrateStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders SetIntegrateStatus: ", err.Error())
-		return
-	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Orders SetIntegrateStatus auth.Validate: ", err.Error())
+		o.log.Error("[SDK] Orders SetIntegrateStatus", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/statuses/integration", v1Endpoint, orderReference)
-	_, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders SetIntegrateStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders SetIntegrateStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders SetIntegrateStatus status code: ", status, " orderReference: ", orderReference)
-		err = fmt.Errorf("Order reference %s could not be integrated", orderReference)
-		glg.Error("[SDK] Orders SetIntegrateStatus err: ", err)
+		o.log.Error("[SDK] Orders SetIntegrateStatus", logger.F("status", status), logger.F("orderReference", orderReference))
+		err = apierror.New(fmt.Sprintf("Order reference %s could not be integrated", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] Orders SetIntegrateStatus err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders SetIntegrateStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders SetIntegrateStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 func (o *ordersService) SetConfirmStatus(orderReference string) (err error) {
+	return o.SetConfirmStatusCtx(context.Background(), orderReference)
+}
+
+// SetConfirmStatusCtx works like SetConfirmStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) SetConfirmStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders SetConfirmStatus: ", err.Error())
-		return
-	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Orders SetConfirmStatus auth.Validate: ", err.Error())
+		o.log.Error("[SDK] Orders SetConfirmStatus", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/statuses/confirmation", v1Endpoint, orderReference)
-	_, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders SetConfirmStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders SetConfirmStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders SetConfirmStatus status code: ", status, " orderReference: ", orderReference)
-		err = fmt.Errorf("Order reference '%s' could not be confirmed", orderReference)
-		glg.Error("[SDK] Orders SetConfirmStatus err: ", err)
+		o.log.Error("[SDK] Orders SetConfirmStatus", logger.F("status", status), logger.F("orderReference", orderReference))
+		err = apierror.New(fmt.Sprintf("Order reference '%s' could not be confirmed", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] Orders SetConfirmStatus err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders SetConfirmStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders SetConfirmStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 // V2SetConfirmStatus trys to update an order to confirmed status
 func (o *ordersService) V2SetConfirmStatus(orderReference string) (err error) {
+	return o.V2SetConfirmStatusCtx(context.Background(), orderReference)
+}
+
+// V2SetConfirmStatusCtx works like V2SetConfirmStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) V2SetConfirmStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] (Orders V2SetConfirmStatus): ", err.Error())
+		o.log.Error("[SDK] (Orders V2SetConfirmStatus)", logger.Err(err))
 		return
 	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] (Orders V2SetConfirmStatus) auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s%s/confirm", newV2Endpoint, orderReference)
-	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] (Orders V2SetConfirmStatus) adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] (Orders V2SetConfirmStatus) adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		errMsg := events.ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Error("[SDK] (Orders V2SetConfirmStatus) status '%d' err: '%s'", status, err.Error())
+		err = apierror.New(fmt.Sprintf("Order '%s' could not be confirmed", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2SetConfirmStatus)", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders V2SetConfirmStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders V2SetConfirmStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 func (o *ordersService) SetDispatchStatus(orderReference string) (err error) {
+	return o.SetDispatchStatusCtx(context.Background(), orderReference)
+}
+
+// SetDispatchStatusCtx works like SetDispatchStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) SetDispatchStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders SetDispatchStatus: ", err.Error())
-		return
-	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Orders SetDispatchStatus auth.Validate: ", err.Error())
+		o.log.Error("[SDK] Orders SetDispatchStatus", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/statuses/dispatch", v1Endpoint, orderReference)
-	_, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders SetDispatchStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders SetDispatchStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders SetDispatchStatus status code: ", status, " orderReference: ", orderReference)
-		err = fmt.Errorf("Order reference '%s' could not be dispatched", orderReference)
-		glg.Error("[SDK] Orders SetDispatchStatus err: ", err)
+		o.log.Error("[SDK] Orders SetDispatchStatus", logger.F("status", status), logger.F("orderReference", orderReference))
+		err = apierror.New(fmt.Sprintf("Order reference '%s' could not be dispatched", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] Orders SetDispatchStatus err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders SetDispatchStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders SetDispatchStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 func (o *ordersService) V2SetDispatchStatus(orderReference string) (err error) {
+	return o.V2SetDispatchStatusCtx(context.Background(), orderReference)
+}
+
+// V2SetDispatchStatusCtx works like V2SetDispatchStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) V2SetDispatchStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] (Orders V2SetDispatchStatus): ", err.Error())
-		return
-	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] (Orders V2SetDispatchStatus) auth.Validate: ", err.Error())
+		o.log.Error("[SDK] (Orders V2SetDispatchStatus)", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s%s/dispatch", newV2Endpoint, orderReference)
-	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] (Orders V2SetDispatchStatus) adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] (Orders V2SetDispatchStatus) adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		errMsg := events.ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Error("[SDK] (Orders V2SetDispatchStatus) status '%d' err: '%s'", status, err.Error())
+		err = apierror.New(fmt.Sprintf("Order '%s' could not be dispatched", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2SetDispatchStatus)", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders V2SetDispatchStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders V2SetDispatchStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 func (o *ordersService) SetReadyToDeliverStatus(orderReference string) (err error) {
+	return o.SetReadyToDeliverStatusCtx(context.Background(), orderReference)
+}
+
+// SetReadyToDeliverStatusCtx works like SetReadyToDeliverStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) SetReadyToDeliverStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders SetReadyToDeliverStatus: ", err.Error())
+		o.log.Error("[SDK] Orders SetReadyToDeliverStatus", logger.Err(err))
 		return
 	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Orders SetReadyToDeliverStatus auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/statuses/readyToDeliver", v2Endpoint, orderReference)
-	_, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders SetReadyToDeliverStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders SetReadyToDeliverStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders SetReadyToDeliverStatus status code: ", status, " orderReference: ", orderReference)
-		err = fmt.Errorf("Order reference '%s' could not be set as 'ready to deliver'", orderReference)
-		glg.Error("[SDK] Orders SetReadyToDeliverStatus err: ", err)
+		o.log.Error("[SDK] Orders SetReadyToDeliverStatus", logger.F("status", status), logger.F("orderReference", orderReference))
+		err = apierror.New(fmt.Sprintf("Order reference '%s' could not be set as 'ready to deliver'", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] Orders SetReadyToDeliverStatus err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders SetReadyToDeliverStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders SetReadyToDeliverStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 func (o *ordersService) V2SetReadyToPickupStatus(orderReference string) (err error) {
+	return o.V2SetReadyToPickupStatusCtx(context.Background(), orderReference)
+}
+
+// V2SetReadyToPickupStatusCtx works like V2SetReadyToPickupStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) V2SetReadyToPickupStatusCtx(ctx context.Context, orderReference string) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] (Orders V2SetReadyToPickupStatus): ", err.Error())
-		return
-	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] (Orders V2SetReadyToPickupStatus) auth.Validate: ", err.Error())
+		o.log.Error("[SDK] (Orders V2SetReadyToPickupStatus)", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s%s/readyToPickup", newV2Endpoint, orderReference)
-	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] (Orders V2SetReadyToPickupStatus) adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] (Orders V2SetReadyToPickupStatus) adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		errMsg := events.ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Error("[SDK] (Orders V2SetReadyToPickupStatus) status '%d' err: '%s'", status, err.Error())
+		err = apierror.New(fmt.Sprintf("Order '%s' could not be set as 'ready to pickup'", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2SetReadyToPickupStatus)", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders V2SetReadyToPickupStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders V2SetReadyToPickupStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 func (o *ordersService) SetCancelStatus(orderReference, code string) (err error) {
+	return o.SetCancelStatusCtx(context.Background(), orderReference, code)
+}
+
+// SetCancelStatusCtx works like SetCancelStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) SetCancelStatusCtx(ctx context.Context, orderReference, code string) (err error) {
 	if err = verifyCancel(orderReference, code); err != nil {
-		glg.Error("[SDK] Orders SetCancelStatus verifyCancel: ", err.Error())
+		o.log.Error("[SDK] Orders SetCancelStatus verifyCancel", logger.Err(err))
 		return
 	}
-	err = o.auth.Validate()
-	if err != nil {
-		glg.Error("[SDK] Orders SetCancelStatus auth.Validate: ", err.Error())
-		return
-	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/statuses/cancellationRequested", v3Endpoint, orderReference)
 	detail := CancelCodes[code]
 	co := cancelOrder{Code: code, Details: detail}
 	reader, err := httpadapter.NewJsonReader(co)
 	if err != nil {
-		glg.Error("[SDK] Orders SetCancelStatus NewJsonReader error: ", err.Error())
+		o.log.Error("[SDK] Orders SetCancelStatus NewJsonReader error", logger.Err(err))
 		return
 	}
-	_, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, reader, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders SetCancelStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders SetCancelStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders SetCancelStatus status code: ", status, " orderReference: ", orderReference)
-		err = fmt.Errorf(
+		o.log.Error("[SDK] Orders SetCancelStatus", logger.F("status", status), logger.F("orderReference", orderReference))
+		err = apierror.New(fmt.Sprintf(
 			"Order reference '%s' could not be set as 'cancelled' code '%s', detail '%s'",
-			orderReference, code, detail)
-		glg.Error("[SDK] Orders SetCancelStatus err: ", err)
+			orderReference, code, detail), status, endpoint, resp)
+		o.log.Error("[SDK] Orders SetCancelStatus err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders SetCancelStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders SetCancelStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 // V2RequestCancelStatus on ifood v2 API
 
 func (o *ordersService) V2RequestCancelStatus(orderReference, code string) (err error) {
-	if err = verifyCancel(orderReference, code); err != nil {
-		glg.Error("[SDK] (Orders::V2RequestCancelStatus) verifyCancel: ", err.Error())
+	return o.V2RequestCancelStatusCtx(context.Background(), orderReference, code)
+}
+
+// V2RequestCancelStatusCtx works like V2RequestCancelStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) V2RequestCancelStatusCtx(ctx context.Context, orderReference, code string) (err error) {
+	switch {
+	case orderReference == "":
+		err = ErrOrderReferenceNotSpecified
+	case code == "":
+		err = ErrCancelCodeNotSpecified
+	}
+	if err != nil {
+		o.log.Error("[SDK] (Orders::V2RequestCancelStatus)", logger.Err(err))
 		return
 	}
-	err = o.auth.Validate()
+	reason, err := o.cancellationReason(ctx, orderReference, code)
 	if err != nil {
-		glg.Error("[SDK] (Orders::V2RequestCancelStatus) auth.Validate: ", err.Error())
+		o.log.Error("[SDK] (Orders::V2RequestCancelStatus) cancellationReason", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s%s/requestCancellation", newV2Endpoint, orderReference)
-	detail := CancelCodes[code]
-	co := v2CancelOrder{Reason: detail, CancellationCode: code}
+	co := v2CancelOrder{Reason: reason.Description, CancellationCode: code}
 	reader, err := httpadapter.NewJsonReader(co)
 	if err != nil {
-		glg.Error("[SDK] (Orders::V2RequestCancelStatus) NewJsonReader error: ", err.Error())
+		o.log.Error("[SDK] (Orders::V2RequestCancelStatus) NewJsonReader error", logger.Err(err))
 		return
 	}
-	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, reader, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, reader, nil)
 	if err != nil {
-		glg.Error("[SDK] (Orders::V2RequestCancelStatus) adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] (Orders::V2RequestCancelStatus) adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		errMsg := events.ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Error("[SDK] (Orders::V2RequestCancelStatus) status '%d' err: '%s'", status, err.Error())
+		err = apierror.New(fmt.Sprintf("Order '%s' could not request cancellation code '%s'", orderReference, code), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders::V2RequestCancelStatus)", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders::V2RequestCancelStatus) '%s' OK", orderReference)
+	o.reasons.drop(orderReference)
+	o.log.Debug("[SDK] (Orders::V2RequestCancelStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
@@ -427,128 +491,326 @@ func (o *ordersService) V2RequestCancelStatus(orderReference, code string) (err
 // reference: order reference id
 // accepted: aceitacao pelo e-PDV do cancelamento do pedido
 func (o *ordersService) ClientCancellationStatus(orderReference string, accepted bool) (err error) {
+	return o.ClientCancellationStatusCtx(context.Background(), orderReference, accepted)
+}
+
+// ClientCancellationStatusCtx works like ClientCancellationStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) ClientCancellationStatusCtx(ctx context.Context, orderReference string, accepted bool) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders ClientCancellationStatus: ", err.Error())
-		return
-	}
-	if err = o.auth.Validate(); err != nil {
-		glg.Error("[SDK] Orders ClientCancellationStatus auth.Validate: ", err.Error())
+		o.log.Error("[SDK] Orders ClientCancellationStatus", logger.Err(err))
 		return
 	}
 	cancelStatus := "consumerCancellationDenied"
 	if accepted {
 		cancelStatus = "consumerCancellationAccepted"
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/statuses/%s", v2Endpoint, orderReference, cancelStatus)
-	_, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders ClientCancellationStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders ClientCancellationStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders ClientCancellationStatus status code: ", status, " orderReference: ", orderReference)
-		err = fmt.Errorf(
+		o.log.Error("[SDK] Orders ClientCancellationStatus", logger.F("status", status), logger.F("orderReference", orderReference))
+		err = apierror.New(fmt.Sprintf(
 			"Order reference '%s' could not set 'client cancellation' status '%s'",
-			orderReference, cancelStatus)
-		glg.Error("[SDK] Orders ClientCancellationStatus err: ", err)
+			orderReference, cancelStatus), status, endpoint, resp)
+		o.log.Error("[SDK] Orders ClientCancellationStatus err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders ClientCancellationStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders ClientCancellationStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 // V2AcceptCancellationStatus lida com o cancelamento do pedido por parte do cliente
 func (o *ordersService) V2ClientCancellationStatus(orderReference string, accepted bool) (err error) {
+	return o.V2ClientCancellationStatusCtx(context.Background(), orderReference, accepted)
+}
+
+// V2ClientCancellationStatusCtx works like V2ClientCancellationStatus, honoring ctx cancellation and deadlines
+func (o *ordersService) V2ClientCancellationStatusCtx(ctx context.Context, orderReference string, accepted bool) (err error) {
 	if orderReference == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders V2AcceptCancellationStatus: ", err.Error())
-		return
-	}
-	if err = o.auth.Validate(); err != nil {
-		glg.Error("[SDK] Orders V2AcceptCancellationStatus auth.Validate: ", err.Error())
+		o.log.Error("[SDK] Orders V2AcceptCancellationStatus", logger.Err(err))
 		return
 	}
 	cancelStatus := "denyCancellation"
 	if accepted {
 		cancelStatus = "acceptCancellation"
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s%s/%s", newV2Endpoint, orderReference, cancelStatus)
-	resp, status, err := o.adapter.DoRequest(http.MethodPost, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders V2AcceptCancellationStatus adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders V2AcceptCancellationStatus adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		errMsg := events.ErrV2API{}
-		json.Unmarshal(resp, &errMsg)
-		err = errors.New(errMsg.Error.Message)
-		glg.Error("[SDK] (Orders::V2AcceptCancellationStatus) status '%d' err: '%s'", status, err.Error())
+		err = apierror.New(fmt.Sprintf("Order '%s' could not set 'client cancellation' status '%s'", orderReference, cancelStatus), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders::V2AcceptCancellationStatus)", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders V2AcceptCancellationStatus) '%s' OK", orderReference)
+	o.log.Debug("[SDK] (Orders V2AcceptCancellationStatus) OK", logger.F("orderReference", orderReference))
 	return
 }
 
 // Tracking retorna a posicao do entregador
 func (o *ordersService) Tracking(orderUUID string) (tr TrackingResponse, err error) {
+	return o.TrackingCtx(context.Background(), orderUUID)
+}
+
+// TrackingCtx works like Tracking, honoring ctx cancellation and deadlines
+func (o *ordersService) TrackingCtx(ctx context.Context, orderUUID string) (tr TrackingResponse, err error) {
 	if orderUUID == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders Tracking: ", orderUUID, " err: ", err.Error())
-		return
-	}
-	if err = o.auth.Validate(); err != nil {
-		glg.Error("[SDK] Orders Tracking auth.Validate: ", err.Error())
+		o.log.Error("[SDK] Orders Tracking", logger.F("orderUUID", orderUUID), logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
 	endpoint := fmt.Sprintf("%s/%s/tracking", v2Endpoint, orderUUID)
-	resp, status, err := o.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders Tracking adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] Orders Tracking adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders Tracking status code: ", status, " order uuid: ", orderUUID)
-		err = fmt.Errorf("Order reference '%s' could not get tracking information", orderUUID)
-		glg.Error("[SDK] Orders Tracking err: ", err)
+		o.log.Error("[SDK] Orders Tracking", logger.F("status", status), logger.F("orderUUID", orderUUID))
+		err = apierror.New(fmt.Sprintf("Order reference '%s' could not get tracking information", orderUUID), status, endpoint, resp)
+		o.log.Error("[SDK] Orders Tracking err", logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders Tracking) '%s' OK", orderUUID)
+	o.log.Debug("[SDK] (Orders Tracking) OK", logger.F("orderUUID", orderUUID))
 	return tr, json.Unmarshal(resp, &tr)
 }
 
 // DeliveryInformation retorna informacoes da entrega
 func (o *ordersService) DeliveryInformation(orderUUID string) (di DeliveryInformationResponse, err error) {
+	return o.DeliveryInformationCtx(context.Background(), orderUUID)
+}
+
+// DeliveryInformationCtx works like DeliveryInformation, honoring ctx cancellation and deadlines
+func (o *ordersService) DeliveryInformationCtx(ctx context.Context, orderUUID string) (di DeliveryInformationResponse, err error) {
 	if orderUUID == "" {
 		err = ErrOrderReferenceNotSpecified
-		glg.Error("[SDK] Orders DeliveryInformation: ", orderUUID, " err: ", err.Error())
+		o.log.Error("[SDK] Orders DeliveryInformation", logger.F("orderUUID", orderUUID), logger.Err(err))
 		return
 	}
-	if err = o.auth.Validate(); err != nil {
-		glg.Error("[SDK] Orders DeliveryInformation auth.Validate: ", err.Error())
+	endpoint := fmt.Sprintf("%s/%s/delivery-information", v2Endpoint, orderUUID)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		o.log.Error("[SDK] Orders DeliveryInformation adapter.DoRequest error", logger.Err(err))
 		return
 	}
-	headers := make(map[string]string)
-	headers["Authorization"] = fmt.Sprintf("Bearer %s", o.auth.GetToken())
-	endpoint := fmt.Sprintf("%s/%s/delivery-information", v2Endpoint, orderUUID)
-	resp, status, err := o.adapter.DoRequest(http.MethodGet, endpoint, nil, headers)
+	if status != http.StatusAccepted {
+		o.log.Error("[SDK] Orders DeliveryInformation", logger.F("status", status), logger.F("orderUUID", orderUUID))
+		err = apierror.New(fmt.Sprintf("Order uuid '%s' could get delivery information", orderUUID), status, endpoint, resp)
+		o.log.Error("[SDK] Orders DeliveryInformation err", logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders DeliveryInformation) OK", logger.F("orderUUID", orderUUID))
+	return di, json.Unmarshal(resp, &di)
+}
+
+// V2StartPreparation tells the order preparation started
+func (o *ordersService) V2StartPreparation(orderReference string) (err error) {
+	return o.V2StartPreparationCtx(context.Background(), orderReference)
+}
+
+// V2StartPreparationCtx works like V2StartPreparation, honoring ctx cancellation and deadlines
+func (o *ordersService) V2StartPreparationCtx(ctx context.Context, orderReference string) (err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		o.log.Error("[SDK] (Orders V2StartPreparation)", logger.Err(err))
+		return
+	}
+	endpoint := fmt.Sprintf("%s%s/startPreparation", newV2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
 	if err != nil {
-		glg.Error("[SDK] Orders DeliveryInformation adapter.DoRequest error: ", err.Error())
+		o.log.Error("[SDK] (Orders V2StartPreparation) adapter.DoRequest error", logger.Err(err))
 		return
 	}
 	if status != http.StatusAccepted {
-		glg.Error("[SDK] Orders DeliveryInformation status code: ", status, " order uuid: ", orderUUID)
-		err = fmt.Errorf("Order uuid '%s' could get delivery information", orderUUID)
-		glg.Error("[SDK] Orders DeliveryInformation err: ", err)
+		err = apierror.New(fmt.Sprintf("Order '%s' could not start preparation", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2StartPreparation)", logger.F("status", status), logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders V2StartPreparation) OK", logger.F("orderReference", orderReference))
+	return
+}
+
+// V2RequestDriver asks for an iFood driver to deliver an order the merchant would deliver
+func (o *ordersService) V2RequestDriver(orderReference string) (err error) {
+	return o.V2RequestDriverCtx(context.Background(), orderReference)
+}
+
+// V2RequestDriverCtx works like V2RequestDriver, honoring ctx cancellation and deadlines
+func (o *ordersService) V2RequestDriverCtx(ctx context.Context, orderReference string) (err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		o.log.Error("[SDK] (Orders V2RequestDriver)", logger.Err(err))
+		return
+	}
+	endpoint := fmt.Sprintf("%s%s/requestDriver", newV2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, nil, nil)
+	if err != nil {
+		o.log.Error("[SDK] (Orders V2RequestDriver) adapter.DoRequest error", logger.Err(err))
+		return
+	}
+	if status != http.StatusAccepted {
+		err = apierror.New(fmt.Sprintf("Order '%s' could not request a driver", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2RequestDriver)", logger.F("status", status), logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders V2RequestDriver) OK", logger.F("orderReference", orderReference))
+	return
+}
+
+// V2CancellationReasons lists the cancellation codes the order accepts,
+// empty when it can no longer be cancelled, the list is cached for
+// CancellationReasonsTTL
+func (o *ordersService) V2CancellationReasons(orderReference string) (reasons []CancellationReason, err error) {
+	return o.V2CancellationReasonsCtx(context.Background(), orderReference)
+}
+
+// V2CancellationReasonsCtx works like V2CancellationReasons, honoring ctx cancellation and deadlines
+func (o *ordersService) V2CancellationReasonsCtx(ctx context.Context, orderReference string) (reasons []CancellationReason, err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		o.log.Error("[SDK] (Orders V2CancellationReasons)", logger.Err(err))
+		return
+	}
+	if cached, ok := o.reasons.get(orderReference, time.Now()); ok {
+		return cached, nil
+	}
+	endpoint := fmt.Sprintf("%s%s/cancellationReasons", newV2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		o.log.Error("[SDK] (Orders V2CancellationReasons) adapter.DoRequest error", logger.Err(err))
+		return
+	}
+	if status == http.StatusNoContent {
+		o.reasons.put(orderReference, nil, time.Now())
+		return
+	}
+	if status != http.StatusOK {
+		err = apierror.New(fmt.Sprintf("Order '%s' could not list cancellation reasons", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2CancellationReasons)", logger.F("status", status), logger.Err(err))
+		return
+	}
+	if err = json.Unmarshal(resp, &reasons); err != nil {
+		return
+	}
+	o.reasons.put(orderReference, reasons, time.Now())
+	o.log.Debug("[SDK] (Orders V2CancellationReasons) OK", logger.F("orderReference", orderReference))
+	return
+}
+
+// V2AcceptDispute accepts the handshake dispute of a HSD event, reason is optional
+func (o *ordersService) V2AcceptDispute(disputeID, reason string) (err error) {
+	return o.V2AcceptDisputeCtx(context.Background(), disputeID, reason)
+}
+
+// V2AcceptDisputeCtx works like V2AcceptDispute, honoring ctx cancellation and deadlines
+func (o *ordersService) V2AcceptDisputeCtx(ctx context.Context, disputeID, reason string) (err error) {
+	if disputeID == "" {
+		err = ErrDisputeNotSpecified
+		o.log.Error("[SDK] (Orders V2AcceptDispute)", logger.Err(err))
+		return
+	}
+	return o.answerDispute(ctx, "V2AcceptDispute", disputeID, "accept", reason)
+}
+
+// V2RejectDispute rejects the handshake dispute of a HSD event
+func (o *ordersService) V2RejectDispute(disputeID, reason string) (err error) {
+	return o.V2RejectDisputeCtx(context.Background(), disputeID, reason)
+}
+
+// V2RejectDisputeCtx works like V2RejectDispute, honoring ctx cancellation and deadlines
+func (o *ordersService) V2RejectDisputeCtx(ctx context.Context, disputeID, reason string) (err error) {
+	switch {
+	case disputeID == "":
+		err = ErrDisputeNotSpecified
+	case reason == "":
+		err = ErrDisputeReasonNotSpecified
+	}
+	if err != nil {
+		o.log.Error("[SDK] (Orders V2RejectDispute)", logger.Err(err))
+		return
+	}
+	return o.answerDispute(ctx, "V2RejectDispute", disputeID, "reject", reason)
+}
+
+func (o *ordersService) answerDispute(ctx context.Context, name, disputeID, answer, reason string) (err error) {
+	endpoint := fmt.Sprintf("%s%s/%s", disputesEndpoint, disputeID, answer)
+	reader, err := httpadapter.NewJsonReader(disputeAnswer{Reason: reason})
+	if err != nil {
+		o.log.Error("[SDK] (Orders "+name+") NewJsonReader error", logger.Err(err))
+		return
+	}
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodPost, endpoint, reader, nil)
+	if err != nil {
+		o.log.Error("[SDK] (Orders "+name+") adapter.DoRequest error", logger.Err(err))
+		return
+	}
+	if status != http.StatusAccepted {
+		err = apierror.New(fmt.Sprintf("Dispute '%s' could not be answered with '%s'", disputeID, answer), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders "+name+")", logger.F("status", status), logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders "+name+") OK", logger.F("disputeID", disputeID))
+	return
+}
+
+// V2Tracking returns the position of the driver of an order
+func (o *ordersService) V2Tracking(orderReference string) (tr TrackingResponse, err error) {
+	return o.V2TrackingCtx(context.Background(), orderReference)
+}
+
+// V2TrackingCtx works like V2Tracking, honoring ctx cancellation and deadlines
+func (o *ordersService) V2TrackingCtx(ctx context.Context, orderReference string) (tr TrackingResponse, err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		o.log.Error("[SDK] (Orders V2Tracking)", logger.Err(err))
+		return
+	}
+	endpoint := fmt.Sprintf("%s%s/tracking", newV2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		o.log.Error("[SDK] (Orders V2Tracking) adapter.DoRequest error", logger.Err(err))
+		return
+	}
+	if status != http.StatusOK {
+		err = apierror.New(fmt.Sprintf("Order '%s' could not get tracking information", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2Tracking)", logger.F("status", status), logger.Err(err))
+		return
+	}
+	o.log.Debug("[SDK] (Orders V2Tracking) OK", logger.F("orderReference", orderReference))
+	return tr, json.Unmarshal(resp, &tr)
+}
+
+// V2DeliveryInformation returns the driver and vehicle delivering an order
+func (o *ordersService) V2DeliveryInformation(orderReference string) (di DeliveryInformationResponse, err error) {
+	return o.V2DeliveryInformationCtx(context.Background(), orderReference)
+}
+
+// V2DeliveryInformationCtx works like V2DeliveryInformation, honoring ctx cancellation and deadlines
+func (o *ordersService) V2DeliveryInformationCtx(ctx context.Context, orderReference string) (di DeliveryInformationResponse, err error) {
+	if orderReference == "" {
+		err = ErrOrderReferenceNotSpecified
+		o.log.Error("[SDK] (Orders V2DeliveryInformation)", logger.Err(err))
+		return
+	}
+	endpoint := fmt.Sprintf("%s%s/deliveryInformation", newV2Endpoint, orderReference)
+	resp, status, err := o.adapter.DoRequestCtx(ctx, http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		o.log.Error("[SDK] (Orders V2DeliveryInformation) adapter.DoRequest error", logger.Err(err))
+		return
+	}
+	if status != http.StatusOK {
+		err = apierror.New(fmt.Sprintf("Order '%s' could not get delivery information", orderReference), status, endpoint, resp)
+		o.log.Error("[SDK] (Orders V2DeliveryInformation)", logger.F("status", status), logger.Err(err))
 		return
 	}
-	glg.Debugf("[SDK] (Orders DeliveryInformation) '%s' OK", orderUUID)
+	o.log.Debug("[SDK] (Orders V2DeliveryInformation) OK", logger.F("orderReference", orderReference))
 	return di, json.Unmarshal(resp, &di)
 }
 
@@ -1,14 +1,19 @@
 package orders
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	"github.com/arxdsilva/golang-ifood-sdk/apierror"
 	"github.com/arxdsilva/golang-ifood-sdk/mocks"
+	"github.com/arxdsilva/golang-ifood-sdk/money"
 	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -277,6 +282,17 @@ func TestGetDetails_OK(t *testing.T) {
 	od, err := ordersService.GetDetails("reference_id")
 	assert.Nil(t, err)
 	assert.Equal(t, "REFERENCIA", od.ID)
+	// the placeholder prices of the documentation are not decimals
+	err = od.AmountErrors()
+	assert.ErrorIs(t, err, money.ErrInvalidAmount)
+	var amountErr *money.AmountError
+	require.True(t, errors.As(err, &amountErr))
+	assert.Len(t, amountErr.Fields, 28)
+	assert.Equal(t, money.FieldError{Path: "payments[0].value", Value: "Valor pago na forma", Err: amountErr.Fields[0].Err}, amountErr.Fields[0])
+	assert.Equal(t, "items[0].subItems[0].price", amountErr.Fields[7].Path)
+	assert.Equal(t, "Total do pedido(Com taxa de entrega)", amountErr.Fields[26].Value)
+	assert.True(t, od.Totalprice.IsZero())
+	assert.True(t, od.Items[0].Price.IsZero())
 }
 
 func TestGetDetails_NoRefereceID(t *testing.T) {
@@ -1067,7 +1083,9 @@ func Test_V2GetDetails_BadRequest(t *testing.T) {
 	assert.NotNil(t, ordersService)
 	_, err := ordersService.V2GetDetails("reference_id")
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
 func Test_V2GetDetails_NoOrderUUID(t *testing.T) {
@@ -1140,7 +1158,9 @@ func Test_V2SetConfirmStatus_BadRequest(t *testing.T) {
 	assert.NotNil(t, ordersService)
 	err := ordersService.V2SetConfirmStatus("reference_id")
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
 func Test_V2SetConfirmStatus_NoRID(t *testing.T) {
@@ -1213,7 +1233,9 @@ func Test_V2SetDispatchStatus_BadRequest(t *testing.T) {
 	assert.NotNil(t, ordersService)
 	err := ordersService.V2SetDispatchStatus("reference_id")
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
 func Test_V2SetDispatchStatus_NoRID(t *testing.T) {
@@ -1309,26 +1331,39 @@ func Test_V2SetReadyToPickupStatus_BadRequest(t *testing.T) {
 	assert.NotNil(t, ordersService)
 	err := ordersService.V2SetReadyToPickupStatus("reference_id")
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
+// cancellationReasonsOK lists the cancellation codes of reference_id
+const cancellationReasonsOK = `[{"cancelCodeId": "501", "description": "PROBLEMAS DE SISTEMA"}, {"cancelCodeId": "525", "description": "PEDIDO NAO PODE SER ENTREGUE"}]`
+
 func Test_V2RequestCancelStatus_OK(t *testing.T) {
 	ts := httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			require.Equal(t, "/order/v1.0/orders/reference_id/requestCancellation", r.URL.Path)
-			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
-			require.Equal(t, r.Method, http.MethodPost)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			if r.Method == http.MethodGet {
+				assert.Equal(t, "/order/v1.0/orders/reference_id/cancellationReasons", r.URL.Path)
+				fmt.Fprint(w, cancellationReasonsOK)
+				return
+			}
+			assert.Equal(t, "/order/v1.0/orders/reference_id/requestCancellation", r.URL.Path)
+			assert.Equal(t, r.Method, http.MethodPost)
+			body, err := ioutil.ReadAll(r.Body)
+			assert.Nil(t, err)
+			assert.JSONEq(t, `{"reason": "PEDIDO NAO PODE SER ENTREGUE", "cancellationCode": "525"}`, string(body))
 			w.WriteHeader(http.StatusAccepted)
 		}),
 	)
 	defer ts.Close()
 	am := auth.AuthMock{}
-	am.On("Validate").Once().Return(nil)
-	am.On("GetToken").Once().Return("token")
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
 	adapter := httpadapter.New(http.DefaultClient, ts.URL)
 	ordersService := New(adapter, &am)
 	assert.NotNil(t, ordersService)
-	err := ordersService.V2RequestCancelStatus("reference_id", "501")
+	err := ordersService.V2RequestCancelStatus("reference_id", "525")
 	assert.Nil(t, err)
 }
 
@@ -1367,23 +1402,28 @@ func Test_V2RequestCancelStatus_StatusBadRequest(t *testing.T) {
 }`
 	ts := httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			require.Equal(t, "/order/v1.0/orders/reference_id/requestCancellation", r.URL.Path)
-			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
-			require.Equal(t, r.Method, http.MethodPost)
+			assert.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, cancellationReasonsOK)
+				return
+			}
+			assert.Equal(t, "/order/v1.0/orders/reference_id/requestCancellation", r.URL.Path)
 			w.WriteHeader(http.StatusBadRequest)
 			fmt.Fprintf(w, resp)
 		}),
 	)
 	defer ts.Close()
 	am := auth.AuthMock{}
-	am.On("Validate").Once().Return(nil)
-	am.On("GetToken").Once().Return("token")
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
 	adapter := httpadapter.New(http.DefaultClient, ts.URL)
 	ordersService := New(adapter, &am)
 	assert.NotNil(t, ordersService)
 	err := ordersService.V2RequestCancelStatus("reference_id", "501")
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
 func Test_V2RequestCancelStatus_DoReqErr(t *testing.T) {
@@ -1400,6 +1440,72 @@ func Test_V2RequestCancelStatus_DoReqErr(t *testing.T) {
 	assert.Contains(t, err.Error(), "some")
 }
 
+func Test_V2RequestCancelStatus_NotAllowed(t *testing.T) {
+	var posts int
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				fmt.Fprint(w, cancellationReasonsOK)
+				return
+			}
+			posts++
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.V2RequestCancelStatus("reference_id", "503")
+	assert.True(t, errors.Is(err, ErrCancelCodeNotAllowed))
+	assert.Equal(t, 0, posts)
+}
+
+func Test_V2RequestCancelStatus_CachedReasons(t *testing.T) {
+	var gets int
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				gets++
+				fmt.Fprint(w, cancellationReasonsOK)
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	reasons, err := ordersService.V2CancellationReasons("reference_id")
+	require.Nil(t, err)
+	assert.Len(t, reasons, 2)
+	assert.NotNil(t, ordersService.V2RequestCancelStatus("reference_id", "501"))
+	assert.NotNil(t, ordersService.V2RequestCancelStatus("reference_id", "501"))
+	assert.Equal(t, 1, gets)
+}
+
+func Test_V2RequestCancelStatus_Offline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	adapter := httpadapter.New(http.DefaultClient, url)
+	ordersService := New(adapter, &am)
+	// the static codes are checked, the request fails afterwards
+	err := ordersService.V2RequestCancelStatus("reference_id", "999")
+	assert.Contains(t, err.Error(), "cancel code '999' is invalid")
+	err = ordersService.V2RequestCancelStatus("reference_id", "501")
+	assert.NotNil(t, err)
+	assert.NotContains(t, err.Error(), "invalid")
+}
+
 func Test_V2ClientCancellationStatus_AcceptOK(t *testing.T) {
 	ts := httptest.NewServer(
 		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1491,7 +1597,9 @@ func Test_V2ClientCancellationStatus_StatusBadRequest(t *testing.T) {
 	assert.NotNil(t, ordersService)
 	err := ordersService.V2ClientCancellationStatus("reference_id", true)
 	assert.NotNil(t, err)
-	assert.Equal(t, "bad request", err.Error())
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad request", apiErr.Message)
 }
 
 func Test_V2ClientCancellationStatus_DoReqErr(t *testing.T) {
@@ -1507,3 +1615,399 @@ func Test_V2ClientCancellationStatus_DoReqErr(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "some")
 }
+
+func Test_V2SetConfirmStatusCtx_DeadlineExceeded(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, newV2Endpoint+"reference_id/confirm", r.URL.Path)
+			<-r.Context().Done()
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := ordersService.V2SetConfirmStatusCtx(ctx, "reference_id")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func Test_V2StartPreparation_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/orders/reference_id/startPreparation", r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			require.Equal(t, r.Method, http.MethodPost)
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	assert.NotNil(t, ordersService)
+	err := ordersService.V2StartPreparation("reference_id")
+	assert.Nil(t, err)
+}
+
+func Test_V2StartPreparation_NoRID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	err := ordersService.V2StartPreparation("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func Test_V2StartPreparation_BadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"error": {"code": "BadRequest", "message": "order is not confirmed"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.V2StartPreparation("reference_id")
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+	assert.Equal(t, "order is not confirmed", apiErr.Message)
+}
+
+func Test_V2RequestDriver_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/orders/reference_id/requestDriver", r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			require.Equal(t, r.Method, http.MethodPost)
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.V2RequestDriver("reference_id")
+	assert.Nil(t, err)
+}
+
+func Test_V2RequestDriver_NoRID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	err := ordersService.V2RequestDriver("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func Test_V2RequestDriver_DoReqErr(t *testing.T) {
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	httpmock := &mocks.HttpClientMock{}
+	httpmock.On("Do", mock.Anything).Once().Return(nil, errors.New("some err"))
+	adapter := httpadapter.New(httpmock, "")
+	ordersService := New(adapter, &am)
+	err := ordersService.V2RequestDriver("reference_id")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "some")
+}
+
+func Test_V2CancellationReasons_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/orders/reference_id/cancellationReasons", r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			require.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `[{"cancelCodeId": "501", "description": "PROBLEMAS DE SISTEMA"}, {"cancelCodeId": "503", "description": "ITEM INDISPONÍVEL"}]`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	reasons, err := ordersService.V2CancellationReasons("reference_id")
+	assert.Nil(t, err)
+	require.Len(t, reasons, 2)
+	assert.Equal(t, CancellationReason{CancelCodeID: "501", Description: "PROBLEMAS DE SISTEMA"}, reasons[0])
+}
+
+func Test_V2CancellationReasons_NoContent(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	reasons, err := ordersService.V2CancellationReasons("reference_id")
+	assert.Nil(t, err)
+	assert.Empty(t, reasons)
+}
+
+func Test_V2CancellationReasons_NoRID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.V2CancellationReasons("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func Test_V2CancellationReasons_NotFound(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	_, err := ordersService.V2CancellationReasons("reference_id")
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+}
+
+func Test_V2AcceptDispute_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/disputes/dispute_id/accept", r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			require.Equal(t, r.Method, http.MethodPost)
+			body, err := ioutil.ReadAll(r.Body)
+			require.Nil(t, err)
+			assert.JSONEq(t, `{}`, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.V2AcceptDispute("dispute_id", "")
+	assert.Nil(t, err)
+}
+
+func Test_V2AcceptDispute_NoDispute(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	err := ordersService.V2AcceptDispute("", "")
+	assert.Equal(t, ErrDisputeNotSpecified, err)
+}
+
+func Test_V2RejectDispute_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/disputes/dispute_id/reject", r.URL.Path)
+			require.Equal(t, r.Method, http.MethodPost)
+			body, err := ioutil.ReadAll(r.Body)
+			require.Nil(t, err)
+			assert.JSONEq(t, `{"reason": "item was delivered"}`, string(body))
+			w.WriteHeader(http.StatusAccepted)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.V2RejectDispute("dispute_id", "item was delivered")
+	assert.Nil(t, err)
+}
+
+func Test_V2RejectDispute_NoReason(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	assert.Equal(t, ErrDisputeNotSpecified, ordersService.V2RejectDispute("", "reason"))
+	assert.Equal(t, ErrDisputeReasonNotSpecified, ordersService.V2RejectDispute("dispute_id", ""))
+}
+
+func Test_V2RejectDispute_Conflict(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprintf(w, `{"error": {"code": "Conflict", "message": "dispute expired"}}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	err := ordersService.V2RejectDispute("dispute_id", "item was delivered")
+	var apiErr *apierror.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "dispute expired", apiErr.Message)
+}
+
+func Test_V2Tracking_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/orders/reference_id/tracking", r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			require.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, trackingOK)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	tr, err := ordersService.V2Tracking("reference_id")
+	assert.Nil(t, err)
+	assert.Equal(t, 10, tr.Eta)
+}
+
+func Test_V2Tracking_NoRID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.V2Tracking("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func Test_V2Tracking_BadRequest(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	_, err := ordersService.V2Tracking("reference_id")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "could not get tracking information")
+}
+
+func Test_V2DeliveryInformation_OK(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/order/v1.0/orders/reference_id/deliveryInformation", r.URL.Path)
+			require.Equal(t, "Bearer token", r.Header["Authorization"][0])
+			require.Equal(t, r.Method, http.MethodGet)
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprintf(w, `{"workerName": "Driver", "vehicleType": "MOTORCYCLE", "eta": 12}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	di, err := ordersService.V2DeliveryInformation("reference_id")
+	assert.Nil(t, err)
+	assert.Equal(t, "Driver", di.WorkerName)
+	assert.Equal(t, 12, di.Eta)
+}
+
+func Test_V2DeliveryInformation_NoRID(t *testing.T) {
+	am := auth.AuthMock{}
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.V2DeliveryInformation("")
+	assert.Equal(t, ErrOrderReferenceNotSpecified, err)
+}
+
+func Test_V2DeliveryInformation_ValidateErr(t *testing.T) {
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(errors.New("validate err"))
+	adapter := httpadapter.New(http.DefaultClient, "")
+	ordersService := New(adapter, &am)
+	_, err := ordersService.V2DeliveryInformation("reference_id")
+	assert.NotNil(t, err)
+	assert.Equal(t, "validate err", err.Error())
+}
+
+func Test_V2GetDetails_DecimalValues(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{
+				"id": "reference_id",
+				"items": [{"unitPrice": 12.9, "quantity": 2, "price": 25.80, "optionsPrice": 1.5, "totalPrice": 27.30}],
+				"total": {"subTotal": 27.30, "deliveryFee": 5.99, "benefits": 2.5, "orderAmount": 30.79},
+				"payments": {"prepaid": 30.79, "pending": 0, "methods": [{"value": 30.79, "currency": "BRL", "cash": {"changeFor": 50}}]},
+				"benefits": [{"value": 2.5, "sponsorshipValues": [{"name": "IFOOD", "value": 2.5}]}]
+			}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	adapter := httpadapter.New(http.DefaultClient, ts.URL)
+	ordersService := New(adapter, &am)
+	od, err := ordersService.V2GetDetails("reference_id")
+	require.Nil(t, err)
+	item := od.Items[0]
+	price, err := item.Unitprice.Mul(int64(item.Quantity))
+	require.Nil(t, err)
+	assert.True(t, price.Equal(item.Price))
+	total, err := money.Sum(od.Total.Subtotal, od.Total.Deliveryfee, od.Total.Benefits.Neg())
+	require.Nil(t, err)
+	assert.True(t, total.Equal(od.Total.Orderamount))
+	assert.Equal(t, "30.79", od.Payments.Methods[0].Value.String())
+	assert.Equal(t, int64(5000), od.Payments.Methods[0].Cash.Changefor.Cents())
+	assert.Equal(t, "2.50", od.Benefits[0].Sponsorshipvalues[0].Value.String())
+}
+
+func Test_V2GetDetails_Currency(t *testing.T) {
+	ts := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, `{
+				"id": "reference_id",
+				"items": [{"unitPrice": 12.9, "quantity": 1, "price": "Preço"}],
+				"total": {"subTotal": 12.90, "orderAmount": 12.90},
+				"payments": {"prepaid": 12.90, "methods": [{"value": 12.90, "currency": "USD"}]}
+			}`)
+		}),
+	)
+	defer ts.Close()
+	am := auth.AuthMock{}
+	am.On("Validate").Once().Return(nil)
+	am.On("GetToken").Once().Return("token")
+	ordersService := New(httpadapter.New(http.DefaultClient, ts.URL), &am)
+	od, err := ordersService.V2GetDetails("reference_id")
+	assert.Nil(t, err)
+	assert.EqualError(t, od.AmountErrors(), "invalid money amount: items[0].price 'Preço'")
+	assert.Equal(t, "reference_id", od.ID)
+	assert.True(t, od.Items[0].Price.IsZero())
+	assert.Equal(t, "USD", od.Payments.Methods[0].Value.Currency())
+	assert.Equal(t, "USD", od.Items[0].Unitprice.Currency())
+	assert.True(t, money.MustParse("12.90").WithCurrency("USD").Equal(od.Total.Orderamount))
+}
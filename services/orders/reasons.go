@@ -0,0 +1,85 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/arxdsilva/golang-ifood-sdk/logger"
+)
+
+// CancellationReasonsTTL is how long the cancellation reasons of an order
+// are cached, they change along the order lifecycle
+const CancellationReasonsTTL = time.Minute
+
+type (
+	// reasonsCache keeps the cancellation reasons by order
+	reasonsCache struct {
+		mu      sync.Mutex
+		entries map[string]cachedReasons
+	}
+
+	cachedReasons struct {
+		reasons []CancellationReason
+		at      time.Time
+	}
+)
+
+func newReasonsCache() *reasonsCache {
+	return &reasonsCache{entries: make(map[string]cachedReasons)}
+}
+
+func (c *reasonsCache) get(orderReference string, now time.Time) ([]CancellationReason, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.entries[orderReference]
+	if !ok || now.Sub(cached.at) >= CancellationReasonsTTL {
+		return nil, false
+	}
+	return cached.reasons, true
+}
+
+// put caches the reasons of an order, dropping the expired ones
+func (c *reasonsCache) put(orderReference string, reasons []CancellationReason, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ref, cached := range c.entries {
+		if now.Sub(cached.at) >= CancellationReasonsTTL {
+			delete(c.entries, ref)
+		}
+	}
+	c.entries[orderReference] = cachedReasons{reasons: reasons, at: now}
+}
+
+func (c *reasonsCache) drop(orderReference string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, orderReference)
+}
+
+// cancellationReason returns the reason of code among the ones the order
+// accepts, the static CancelCodes are used when iFood cannot be reached
+func (o *ordersService) cancellationReason(ctx context.Context, orderReference, code string) (reason CancellationReason, err error) {
+	reasons, err := o.V2CancellationReasonsCtx(ctx, orderReference)
+	var netErr net.Error
+	if err != nil && ctx.Err() == nil && errors.As(err, &netErr) {
+		o.log.Warn("[SDK] (Orders cancellationReason) using the static cancel codes", logger.Err(err))
+		if err = verifyCancel(orderReference, code); err != nil {
+			return
+		}
+		return CancellationReason{CancelCodeID: code, Description: CancelCodes[code]}, nil
+	}
+	if err != nil {
+		return
+	}
+	for _, r := range reasons {
+		if r.CancelCodeID == code {
+			return r, nil
+		}
+	}
+	err = fmt.Errorf("%w: code '%s' for order '%s'", ErrCancelCodeNotAllowed, code, orderReference)
+	return
+}
@@ -0,0 +1,314 @@
+package orders
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+)
+
+// OrderType is the V2 order type
+type OrderType string
+
+// V2 order types
+const (
+	OrderTypeDelivery OrderType = "DELIVERY"
+	OrderTypeTakeout  OrderType = "TAKEOUT"
+	OrderTypeIndoor   OrderType = "INDOOR"
+)
+
+// DeliveredBy tells who delivers a DELIVERY order
+type DeliveredBy string
+
+// V2 delivery modes
+const (
+	DeliveredByMerchant DeliveredBy = "MERCHANT"
+	DeliveredByIFood    DeliveredBy = "IFOOD"
+)
+
+// OrderState is the lifecycle state of an order
+type OrderState string
+
+// V2 order lifecycle states
+const (
+	StatePlaced                OrderState = "PLACED"
+	StateConfirmed             OrderState = "CONFIRMED"
+	StateReadyToPickup         OrderState = "READY_TO_PICKUP"
+	StateDispatched            OrderState = "DISPATCHED"
+	StateConcluded             OrderState = "CONCLUDED"
+	StateCancellationRequested OrderState = "CANCELLATION_REQUESTED"
+	StateCancelled             OrderState = "CANCELLED"
+)
+
+// Action is a call the merchant makes on an order
+type Action string
+
+// Actions of the V2 order lifecycle
+const (
+	ActionConfirm             Action = "confirm"
+	ActionStartPreparation    Action = "startPreparation"
+	ActionReadyToPickup       Action = "readyToPickup"
+	ActionDispatch            Action = "dispatch"
+	ActionRequestCancellation Action = "requestCancellation"
+	ActionAcceptCancellation  Action = "acceptCancellation"
+	ActionDenyCancellation    Action = "denyCancellation"
+)
+
+// progress orders the lifecycle states, events never move an order back
+var progress = map[OrderState]int{
+	StatePlaced:        0,
+	StateConfirmed:     1,
+	StateReadyToPickup: 2,
+	StateDispatched:    3,
+	StateConcluded:     4,
+}
+
+// eventStates are the states the V2 events move an order to
+var eventStates = map[events.EventCode]OrderState{
+	events.EventPlaced:        StatePlaced,
+	events.EventConfirmed:     StateConfirmed,
+	events.EventReadyToPickup: StateReadyToPickup,
+	events.EventDispatched:    StateDispatched,
+	events.EventConcluded:     StateConcluded,
+}
+
+// TransitionError the action is not allowed in the order state
+type TransitionError struct {
+	OrderID string
+	State   OrderState
+	Action  Action
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("order '%s' cannot %s when %s", e.OrderID, e.Action, e.State)
+}
+
+// Is makes errors.Is(err, ErrInvalidTransition) match
+func (e *TransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// OrderStateMachine tracks the lifecycle of a V2 order from its events,
+// the actions it allows depend on the order type and who delivers it,
+// the others are refused with a *TransitionError before reaching iFood.
+// It is safe for concurrent use
+type OrderStateMachine struct {
+	orders      Service
+	orderID     string
+	merchantID  string
+	orderType   OrderType
+	deliveredBy DeliveredBy
+
+	mu        sync.Mutex
+	state     OrderState
+	previous  OrderState
+	preparing bool
+	// consumerCancellation the customer asked to cancel, the merchant
+	// accepts or denies it
+	consumerCancellation bool
+}
+
+// NewOrderStateMachine returns the state machine of a placed order,
+// the actions are sent through orders
+func NewOrderStateMachine(orders Service, orderID string, orderType OrderType, deliveredBy DeliveredBy) *OrderStateMachine {
+	return &OrderStateMachine{orders: orders, orderID: orderID, orderType: orderType, deliveredBy: deliveredBy, state: StatePlaced}
+}
+
+// NewOrderStateMachineFor returns the state machine of a placed order from
+// its details, the actions are sent on behalf of the order merchant
+// unless their ctx carries one
+func NewOrderStateMachineFor(orders Service, od V2OrderDetails) *OrderStateMachine {
+	m := NewOrderStateMachine(orders, od.ID, OrderType(od.Ordertype), DeliveredBy(od.Delivery.Deliveredby))
+	m.merchantID = od.Merchant.ID
+	return m
+}
+
+// State returns the current state of the order
+func (m *OrderStateMachine) State() OrderState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// Allowed returns the actions allowed in the current state
+func (m *OrderStateMachine) Allowed() []Action {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.allowed()
+}
+
+// Can returns a *TransitionError when action is not allowed in the current state
+func (m *OrderStateMachine) Can(action Action) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.can(action)
+}
+
+// Apply advances the order with one of its events, events of states the
+// order is already past are ignored, since iFood may deliver them late
+func (m *OrderStateMachine) Apply(e events.V2Event) error {
+	if e.Orderid != m.orderID {
+		return fmt.Errorf("%w: event of order '%s' applied to order '%s'", ErrOrderMismatch, e.Orderid, m.orderID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.state == StateConcluded || m.state == StateCancelled {
+		return nil
+	}
+	switch e.Code {
+	case events.EventCancelled, events.EventConsumerCancellationAccepted:
+		m.state, m.consumerCancellation = StateCancelled, false
+	case events.EventCancellationRequested:
+		m.requestCancellation()
+	case events.EventCancellationRequestFailed:
+		if m.state == StateCancellationRequested {
+			m.state = m.previous
+		}
+	case events.EventPreparationStarted, events.EventSeparationStarted:
+		// grocery orders are separated instead of prepared
+		m.preparing = true
+	case events.EventConsumerCancellationRequested:
+		m.consumerCancellation = true
+	case events.EventConsumerCancellationDenied:
+		m.consumerCancellation = false
+	default:
+		if next, ok := eventStates[e.Code]; ok {
+			m.advance(next)
+		}
+	}
+	return nil
+}
+
+// Confirm confirms the order
+func (m *OrderStateMachine) Confirm(ctx context.Context) error {
+	return m.do(ctx, ActionConfirm, func(ctx context.Context) error {
+		return m.orders.V2SetConfirmStatusCtx(ctx, m.orderID)
+	}, func() { m.advance(StateConfirmed) })
+}
+
+// StartPreparation tells the preparation of a confirmed order started
+func (m *OrderStateMachine) StartPreparation(ctx context.Context) error {
+	return m.do(ctx, ActionStartPreparation, func(ctx context.Context) error {
+		return m.orders.V2StartPreparationCtx(ctx, m.orderID)
+	}, func() { m.preparing = true })
+}
+
+// ReadyToPickup tells the order is ready to be picked up
+func (m *OrderStateMachine) ReadyToPickup(ctx context.Context) error {
+	return m.do(ctx, ActionReadyToPickup, func(ctx context.Context) error {
+		return m.orders.V2SetReadyToPickupStatusCtx(ctx, m.orderID)
+	}, func() { m.advance(StateReadyToPickup) })
+}
+
+// Dispatch dispatches an order the merchant delivers
+func (m *OrderStateMachine) Dispatch(ctx context.Context) error {
+	return m.do(ctx, ActionDispatch, func(ctx context.Context) error {
+		return m.orders.V2SetDispatchStatusCtx(ctx, m.orderID)
+	}, func() { m.advance(StateDispatched) })
+}
+
+// RequestCancellation asks iFood to cancel the order, it is cancelled
+// once the CAN event arrives
+func (m *OrderStateMachine) RequestCancellation(ctx context.Context, code string) error {
+	return m.do(ctx, ActionRequestCancellation, func(ctx context.Context) error {
+		return m.orders.V2RequestCancelStatusCtx(ctx, m.orderID, code)
+	}, m.requestCancellation)
+}
+
+// AcceptCancellation accepts the cancellation the customer requested
+func (m *OrderStateMachine) AcceptCancellation(ctx context.Context) error {
+	return m.do(ctx, ActionAcceptCancellation, func(ctx context.Context) error {
+		return m.orders.V2ClientCancellationStatusCtx(ctx, m.orderID, true)
+	}, func() { m.state, m.consumerCancellation = StateCancelled, false })
+}
+
+// DenyCancellation denies the cancellation the customer requested
+func (m *OrderStateMachine) DenyCancellation(ctx context.Context) error {
+	return m.do(ctx, ActionDenyCancellation, func(ctx context.Context) error {
+		return m.orders.V2ClientCancellationStatusCtx(ctx, m.orderID, false)
+	}, func() { m.consumerCancellation = false })
+}
+
+// do sends the action when it is allowed and applies it once iFood
+// accepted it, the lock is held so the same action is not sent twice
+func (m *OrderStateMachine) do(ctx context.Context, action Action, send func(ctx context.Context) error, apply func()) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.can(action); err != nil {
+		return err
+	}
+	if auth.MerchantFromContext(ctx) == "" {
+		ctx = auth.WithMerchant(ctx, m.merchantID)
+	}
+	if err := send(ctx); err != nil {
+		return err
+	}
+	apply()
+	return nil
+}
+
+func (m *OrderStateMachine) can(action Action) error {
+	for _, allowed := range m.allowed() {
+		if allowed == action {
+			return nil
+		}
+	}
+	return &TransitionError{OrderID: m.orderID, State: m.state, Action: action}
+}
+
+func (m *OrderStateMachine) allowed() (actions []Action) {
+	switch m.state {
+	case StatePlaced:
+		actions = append(actions, ActionConfirm, ActionRequestCancellation)
+	case StateConfirmed:
+		if !m.preparing {
+			actions = append(actions, ActionStartPreparation)
+		}
+		if m.merchantDelivers() {
+			actions = append(actions, ActionDispatch)
+		} else {
+			actions = append(actions, ActionReadyToPickup)
+		}
+		actions = append(actions, ActionRequestCancellation)
+	case StateReadyToPickup:
+		actions = append(actions, ActionRequestCancellation)
+	case StateDispatched:
+		// the merchant courier left, iFood no longer takes a cancellation
+		// request but the customer may still ask for one
+	case StateCancellationRequested:
+		// iFood answers the request with CAN or CARF, meanwhile only
+		// the customer cancellation can be answered
+	case StateConcluded, StateCancelled:
+		return nil
+	}
+	if m.consumerCancellation {
+		actions = append(actions, ActionAcceptCancellation, ActionDenyCancellation)
+	}
+	return
+}
+
+// merchantDelivers tells whether the merchant dispatches the order,
+// the others are made ready to pickup
+func (m *OrderStateMachine) merchantDelivers() bool {
+	return m.orderType == OrderTypeDelivery && m.deliveredBy == DeliveredByMerchant
+}
+
+// advance moves the order forward, while a cancellation is requested the
+// state it returns to if the request fails moves instead
+func (m *OrderStateMachine) advance(next OrderState) {
+	current := &m.state
+	if m.state == StateCancellationRequested {
+		current = &m.previous
+	}
+	if progress[next] > progress[*current] {
+		*current = next
+	}
+}
+
+func (m *OrderStateMachine) requestCancellation() {
+	if m.state != StateCancellationRequested {
+		m.state, m.previous = StateCancellationRequested, m.state
+	}
+}
@@ -0,0 +1,198 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	httpadapter "github.com/arxdsilva/golang-ifood-sdk/adapters/http"
+	auth "github.com/arxdsilva/golang-ifood-sdk/services/authentication"
+	"github.com/arxdsilva/golang-ifood-sdk/services/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stateServer answers every call with status, recording the paths,
+// the cancellation reasons are listed
+func stateServer(t *testing.T, status int) (Service, func() []string) {
+	var mu sync.Mutex
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `[{"cancelCodeId": "501", "description": "PROBLEMAS DE SISTEMA"}]`)
+			return
+		}
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(ts.Close)
+	am := auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	return New(httpadapter.New(http.DefaultClient, ts.URL), &am), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), paths...)
+	}
+}
+
+func TestOrderStateMachine_MerchantDelivery(t *testing.T) {
+	svc, paths := stateServer(t, http.StatusAccepted)
+	ctx := context.Background()
+	m := NewOrderStateMachine(svc, "order", OrderTypeDelivery, DeliveredByMerchant)
+	assert.Equal(t, StatePlaced, m.State())
+	assert.Equal(t, []Action{ActionConfirm, ActionRequestCancellation}, m.Allowed())
+
+	err := m.Dispatch(ctx)
+	require.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidTransition))
+	var te *TransitionError
+	require.True(t, errors.As(err, &te))
+	assert.Equal(t, StatePlaced, te.State)
+	assert.Equal(t, ActionDispatch, te.Action)
+	assert.Empty(t, paths())
+
+	require.Nil(t, m.Confirm(ctx))
+	assert.Equal(t, StateConfirmed, m.State())
+	assert.Equal(t, []Action{ActionStartPreparation, ActionDispatch, ActionRequestCancellation}, m.Allowed())
+	assert.True(t, errors.Is(m.ReadyToPickup(ctx), ErrInvalidTransition))
+	require.Nil(t, m.StartPreparation(ctx))
+	assert.Equal(t, []Action{ActionDispatch, ActionRequestCancellation}, m.Allowed())
+	require.Nil(t, m.Dispatch(ctx))
+	assert.Equal(t, StateDispatched, m.State())
+	assert.Empty(t, m.Allowed())
+	assert.Equal(t, []string{
+		"/order/v1.0/orders/order/confirm",
+		"/order/v1.0/orders/order/startPreparation",
+		"/order/v1.0/orders/order/dispatch",
+	}, paths())
+}
+
+func TestOrderStateMachine_Takeout(t *testing.T) {
+	svc, _ := stateServer(t, http.StatusAccepted)
+	ctx := context.Background()
+	for _, m := range []*OrderStateMachine{
+		NewOrderStateMachine(svc, "order", OrderTypeTakeout, ""),
+		NewOrderStateMachine(svc, "order", OrderTypeDelivery, DeliveredByIFood),
+		NewOrderStateMachineFor(svc, V2OrderDetails{ID: "order", Ordertype: "INDOOR"}),
+	} {
+		require.Nil(t, m.Confirm(ctx))
+		assert.Equal(t, []Action{ActionStartPreparation, ActionReadyToPickup, ActionRequestCancellation}, m.Allowed())
+		assert.True(t, errors.Is(m.Dispatch(ctx), ErrInvalidTransition))
+		require.Nil(t, m.ReadyToPickup(ctx))
+		assert.Equal(t, StateReadyToPickup, m.State())
+		assert.Equal(t, []Action{ActionRequestCancellation}, m.Allowed())
+	}
+}
+
+// merchantResolver records the merchants the calls are made for
+type merchantResolver struct {
+	*auth.AuthMock
+	mu        sync.Mutex
+	merchants []string
+}
+
+func (r *merchantResolver) ForMerchant(ctx context.Context, merchantID string) (auth.Service, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.merchants = append(r.merchants, merchantID)
+	return r.AuthMock, nil
+}
+
+func TestOrderStateMachine_Merchant(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+	am := &auth.AuthMock{}
+	am.On("Validate").Return(nil)
+	am.On("GetToken").Return("token")
+	resolver := &merchantResolver{AuthMock: am}
+	svc := New(httpadapter.New(http.DefaultClient, ts.URL), resolver)
+	ctx := context.Background()
+	assert.ErrorIs(t, svc.V2SetConfirmStatusCtx(ctx, "order"), auth.ErrMerchantRequired)
+	m := NewOrderStateMachineFor(svc, V2OrderDetails{ID: "order", Ordertype: "TAKEOUT", Merchant: V2MerchantInfos{ID: "m1"}})
+	require.Nil(t, m.Confirm(ctx))
+	require.Nil(t, m.ReadyToPickup(auth.WithMerchant(ctx, "m2")))
+	assert.Equal(t, []string{"m1", "m2"}, resolver.merchants)
+}
+
+func TestOrderStateMachine_Apply(t *testing.T) {
+	m := NewOrderStateMachine(nil, "order", OrderTypeDelivery, DeliveredByIFood)
+	apply := func(code events.EventCode) {
+		require.Nil(t, m.Apply(events.V2Event{Orderid: "order", Code: code}))
+	}
+	apply(events.EventConfirmed)
+	assert.Equal(t, StateConfirmed, m.State())
+	apply(events.EventPreparationStarted)
+	assert.Equal(t, []Action{ActionReadyToPickup, ActionRequestCancellation}, m.Allowed())
+	apply(events.EventPlaced)
+	assert.Equal(t, StateConfirmed, m.State())
+
+	apply(events.EventCancellationRequested)
+	assert.Equal(t, StateCancellationRequested, m.State())
+	assert.Empty(t, m.Allowed())
+	apply(events.EventReadyToPickup)
+	apply(events.EventCancellationRequestFailed)
+	assert.Equal(t, StateReadyToPickup, m.State())
+
+	apply(events.EventConsumerCancellationRequested)
+	assert.Equal(t, []Action{ActionRequestCancellation, ActionAcceptCancellation, ActionDenyCancellation}, m.Allowed())
+	apply(events.EventConsumerCancellationDenied)
+	assert.Equal(t, []Action{ActionRequestCancellation}, m.Allowed())
+
+	apply(events.EventDispatched)
+	assert.Equal(t, StateDispatched, m.State())
+	assert.Empty(t, m.Allowed())
+	apply(events.EventConsumerCancellationRequested)
+	assert.Equal(t, []Action{ActionAcceptCancellation, ActionDenyCancellation}, m.Allowed())
+	apply(events.EventConcluded)
+	assert.Equal(t, StateConcluded, m.State())
+	apply(events.EventCancelled)
+	assert.Equal(t, StateConcluded, m.State())
+
+	err := m.Apply(events.V2Event{Orderid: "other", Code: events.EventCancelled})
+	assert.True(t, errors.Is(err, ErrOrderMismatch))
+}
+
+func TestOrderStateMachine_GrocerySeparation(t *testing.T) {
+	m := NewOrderStateMachine(nil, "order", OrderTypeDelivery, DeliveredByIFood)
+	require.Nil(t, m.Apply(events.V2Event{Orderid: "order", Code: events.EventConfirmed}))
+	require.Nil(t, m.Apply(events.V2Event{Orderid: "order", Code: events.EventSeparationStarted}))
+	assert.Equal(t, []Action{ActionReadyToPickup, ActionRequestCancellation}, m.Allowed())
+}
+
+func TestOrderStateMachine_Cancellation(t *testing.T) {
+	svc, paths := stateServer(t, http.StatusAccepted)
+	ctx := context.Background()
+	m := NewOrderStateMachine(svc, "order", OrderTypeTakeout, "")
+	require.Nil(t, m.RequestCancellation(ctx, "501"))
+	assert.Equal(t, StateCancellationRequested, m.State())
+	assert.Empty(t, m.Allowed())
+	assert.True(t, errors.Is(m.RequestCancellation(ctx, "501"), ErrInvalidTransition))
+	require.Nil(t, m.Apply(events.V2Event{Orderid: "order", Code: events.EventCancelled}))
+	assert.Equal(t, StateCancelled, m.State())
+	assert.Empty(t, m.Allowed())
+
+	m = NewOrderStateMachine(svc, "order", OrderTypeTakeout, "")
+	assert.True(t, errors.Is(m.AcceptCancellation(ctx), ErrInvalidTransition))
+	require.Nil(t, m.Apply(events.V2Event{Orderid: "order", Code: events.EventConsumerCancellationRequested}))
+	require.Nil(t, m.AcceptCancellation(ctx))
+	assert.Equal(t, StateCancelled, m.State())
+	assert.Equal(t, []string{"/order/v1.0/orders/order/requestCancellation", "/order/v1.0/orders/order/acceptCancellation"}, paths())
+}
+
+func TestOrderStateMachine_Refused(t *testing.T) {
+	svc, _ := stateServer(t, http.StatusBadRequest)
+	m := NewOrderStateMachine(svc, "order", OrderTypeTakeout, "")
+	err := m.Confirm(context.Background())
+	require.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrInvalidTransition))
+	assert.Equal(t, StatePlaced, m.State())
+}
@@ -3,6 +3,7 @@ package orders
 import (
 	"time"
 
+	"github.com/arxdsilva/golang-ifood-sdk/money"
 	"github.com/arxdsilva/golang-ifood-sdk/services/merchant"
 )
 
@@ -18,9 +19,9 @@ type (
 		Payments                 []Payment         `json:"payments"`
 		Customer                 Customer          `json:"customer"`
 		Items                    []Item            `json:"items"`
-		Subtotal                 string            `json:"subTotal"`
-		Totalprice               string            `json:"totalPrice"`
-		Deliveryfee              string            `json:"deliveryFee"`
+		Subtotal                 money.Money       `json:"subTotal"`
+		Totalprice               money.Money       `json:"totalPrice"`
+		Deliveryfee              money.Money       `json:"deliveryFee"`
 		Deliveryaddress          DeliveryAddress   `json:"deliveryAddress"`
 		Deliverydatetime         string            `json:"deliveryDateTime"`
 		Preparationtimeinseconds string            `json:"preparationTimeInSeconds"`
@@ -28,12 +29,12 @@ type (
 
 	// Payment details
 	Payment struct {
-		Name      string `json:"name"`
-		Code      string `json:"code"`
-		Value     string `json:"value"`
-		Prepaid   string `json:"prepaid"`
-		Issuer    string `json:"issuer"`
-		Collector string `json:"collector,omitempty"`
+		Name      string      `json:"name"`
+		Code      string      `json:"code"`
+		Value     money.Money `json:"value"`
+		Prepaid   string      `json:"prepaid"`
+		Issuer    string      `json:"issuer"`
+		Collector string      `json:"collector,omitempty"`
 	}
 
 	// Customer details
@@ -81,27 +82,27 @@ type (
 
 	// Item of the order
 	Item struct {
-		Name          string    `json:"name"`
-		Quantity      string    `json:"quantity"`
-		Price         string    `json:"price"`
-		Subitemsprice string    `json:"subItemsPrice"`
-		Totalprice    string    `json:"totalPrice"`
-		Discount      string    `json:"discount"`
-		Addition      string    `json:"addition"`
-		Externalcode  string    `json:"externalCode,omitempty"`
-		Subitems      []Subitem `json:"subItems,omitempty"`
-		Observations  string    `json:"observations,omitempty"`
+		Name          string      `json:"name"`
+		Quantity      string      `json:"quantity"`
+		Price         money.Money `json:"price"`
+		Subitemsprice money.Money `json:"subItemsPrice"`
+		Totalprice    money.Money `json:"totalPrice"`
+		Discount      money.Money `json:"discount"`
+		Addition      money.Money `json:"addition"`
+		Externalcode  string      `json:"externalCode,omitempty"`
+		Subitems      []Subitem   `json:"subItems,omitempty"`
+		Observations  string      `json:"observations,omitempty"`
 	}
 
 	// Subitem of the order
 	Subitem struct {
-		Name         string `json:"name"`
-		Quantity     string `json:"quantity"`
-		Price        string `json:"price"`
-		Totalprice   string `json:"totalPrice"`
-		Discount     string `json:"discount"`
-		Addition     string `json:"addition"`
-		Externalcode string `json:"externalCode"`
+		Name         string      `json:"name"`
+		Quantity     string      `json:"quantity"`
+		Price        money.Money `json:"price"`
+		Totalprice   money.Money `json:"totalPrice"`
+		Discount     money.Money `json:"discount"`
+		Addition     money.Money `json:"addition"`
+		Externalcode string      `json:"externalCode"`
 	}
 
 	// TrackingResponse API response of tracking
@@ -141,6 +142,16 @@ type (
 		CancellationCode string `json:"cancellationCode"`
 	}
 
+	// CancellationReason is a cancellation code the order accepts
+	CancellationReason struct {
+		CancelCodeID string `json:"cancelCodeId"`
+		Description  string `json:"description"`
+	}
+
+	disputeAnswer struct {
+		Reason string `json:"reason,omitempty"`
+	}
+
 	V2OrderDetails struct {
 		Benefits                 []V2Benefit           `json:"benefits"`
 		Ordertype                string                `json:"orderType"`
@@ -163,29 +174,29 @@ type (
 	}
 
 	V2Item struct {
-		Unitprice    int    `json:"unitPrice"`
-		Quantity     int    `json:"quantity"`
-		Externalcode string `json:"externalCode"`
-		Totalprice   int    `json:"totalPrice"`
-		Index        int    `json:"index"`
-		Unit         string `json:"unit"`
-		Ean          string `json:"ean"`
-		Price        int    `json:"price"`
-		Observations string `json:"observations"`
-		Name         string `json:"name"`
+		Unitprice    money.Money `json:"unitPrice"`
+		Quantity     int         `json:"quantity"`
+		Externalcode string      `json:"externalCode"`
+		Totalprice   money.Money `json:"totalPrice"`
+		Index        int         `json:"index"`
+		Unit         string      `json:"unit"`
+		Ean          string      `json:"ean"`
+		Price        money.Money `json:"price"`
+		Observations string      `json:"observations"`
+		Name         string      `json:"name"`
 		Options      []struct {
-			Unitprice    int    `json:"unitPrice"`
-			Unit         string `json:"unit"`
-			Ean          string `json:"ean"`
-			Quantity     int    `json:"quantity"`
-			Externalcode string `json:"externalCode"`
-			Price        int    `json:"price"`
-			Name         string `json:"name"`
-			Index        int    `json:"index"`
-			ID           string `json:"id"`
+			Unitprice    money.Money `json:"unitPrice"`
+			Unit         string      `json:"unit"`
+			Ean          string      `json:"ean"`
+			Quantity     int         `json:"quantity"`
+			Externalcode string      `json:"externalCode"`
+			Price        money.Money `json:"price"`
+			Name         string      `json:"name"`
+			Index        int         `json:"index"`
+			ID           string      `json:"id"`
 		} `json:"options"`
-		ID           string `json:"id"`
-		Optionsprice int    `json:"optionsPrice"`
+		ID           string      `json:"id"`
+		Optionsprice money.Money `json:"optionsPrice"`
 	}
 
 	V2DeliveryInformation struct {
@@ -215,37 +226,37 @@ type (
 			Wallet struct {
 				Name string `json:"name"`
 			} `json:"wallet"`
-			Method   string `json:"method"`
-			Prepaid  bool   `json:"prepaid"`
-			Currency string `json:"currency"`
-			Type     string `json:"type"`
-			Value    int    `json:"value"`
+			Method   string      `json:"method"`
+			Prepaid  bool        `json:"prepaid"`
+			Currency string      `json:"currency"`
+			Type     string      `json:"type"`
+			Value    money.Money `json:"value"`
 			Cash     struct {
-				Changefor int `json:"changeFor"`
+				Changefor money.Money `json:"changeFor"`
 			} `json:"cash"`
 			Card struct {
 				Brand string `json:"brand"`
 			} `json:"card"`
 		} `json:"methods"`
-		Pending int `json:"pending"`
-		Prepaid int `json:"prepaid"`
+		Pending money.Money `json:"pending"`
+		Prepaid money.Money `json:"prepaid"`
 	}
 
 	V2OrderValues struct {
-		Benefits    int `json:"benefits"`
-		Deliveryfee int `json:"deliveryFee"`
-		Orderamount int `json:"orderAmount"`
-		Subtotal    int `json:"subTotal"`
+		Benefits    money.Money `json:"benefits"`
+		Deliveryfee money.Money `json:"deliveryFee"`
+		Orderamount money.Money `json:"orderAmount"`
+		Subtotal    money.Money `json:"subTotal"`
 	}
 
 	V2Benefit struct {
 		Targetid          string `json:"targetId"`
 		Sponsorshipvalues []struct {
-			Name  string `json:"name"`
-			Value int    `json:"value"`
+			Name  string      `json:"name"`
+			Value money.Money `json:"value"`
 		} `json:"sponsorshipValues"`
-		Value  int    `json:"value"`
-		Target string `json:"target"`
+		Value  money.Money `json:"value"`
+		Target string      `json:"target"`
 	}
 
 	V2MerchantInfos struct {